@@ -1,6 +1,9 @@
 package appctx
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
 	"github.com/KevinHaeusler/go-haruki/bot/clients/radarr"
 	"github.com/KevinHaeusler/go-haruki/bot/clients/sonarr"
@@ -8,14 +11,52 @@ import (
 
 	"github.com/KevinHaeusler/go-haruki/bot/config"
 	"github.com/KevinHaeusler/go-haruki/bot/httpx"
+	"github.com/KevinHaeusler/go-haruki/bot/limits"
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
 )
 
 type Context struct {
 	Config config.Config
 	HTTP   *httpx.Client
+	Perms  *permissions.Policy
+	Limits *limits.Limiter
+	Log    *slog.Logger
 
 	Jelly    *jellyseerr.Client
 	Tautulli *tautulli.Client
 	Sonarr   *sonarr.Client
 	Radarr   *radarr.Client
+
+	ctx context.Context
+	cid string
+}
+
+// WithCorrelation returns a copy whose logger tags every line with the
+// correlation ID cid and the given attributes. Use one copy per interaction.
+func (c *Context) WithCorrelation(cid string, attrs ...any) *Context {
+	cp := *c
+	base := c.Log
+	if base == nil {
+		base = slog.Default()
+	}
+	cp.Log = base.With(append([]any{"cid", cid}, attrs...)...)
+	cp.cid = cid
+	cp.ctx = logging.WithLogger(context.Background(), cp.Log)
+	return &cp
+}
+
+// CorrelationID returns the ID set by WithCorrelation, shown to users as an
+// error reference.
+func (c *Context) CorrelationID() string {
+	return c.cid
+}
+
+// Context returns a context.Context carrying the logger, for upstream calls
+// whose log lines should share this interaction's correlation ID.
+func (c *Context) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
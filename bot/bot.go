@@ -3,7 +3,8 @@ package bot
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -20,13 +21,29 @@ import (
 	"github.com/KevinHaeusler/go-haruki/bot/commands"
 	"github.com/KevinHaeusler/go-haruki/bot/config"
 	"github.com/KevinHaeusler/go-haruki/bot/handlers"
+	"github.com/KevinHaeusler/go-haruki/bot/health"
 	"github.com/KevinHaeusler/go-haruki/bot/httpx"
+	"github.com/KevinHaeusler/go-haruki/bot/limits"
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
+	"github.com/KevinHaeusler/go-haruki/bot/metrics"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
+	"github.com/KevinHaeusler/go-haruki/bot/session"
 	"github.com/KevinHaeusler/go-haruki/bot/ui"
 	"github.com/KevinHaeusler/go-haruki/bot/webhooks"
 )
 
 var Session *discordgo.Session
-var webhookServerStop func()
+var httpServers []*http.Server
+var sessionDB *session.DB
+
+// stopBackground cancels the user index refresher and the session janitor.
+var stopBackground context.CancelFunc
+
+// sessionSweepInterval is how often expired sessions are closed out.
+const sessionSweepInterval = 10 * time.Second
+
+// healthCheckTimeout bounds each dependency probe behind /healthz and /readyz.
+const healthCheckTimeout = 5 * time.Second
 
 // dedupe recent webhook notifications
 var (
@@ -49,49 +66,87 @@ func webhookKey(p webhooks.NotificationPayload) string {
 	return fmt.Sprintf("%s|%s|%s|%s", event, mediaType, mediaID, subject)
 }
 
-func suppressIfRecent(p webhooks.NotificationPayload, window time.Duration) bool {
+func suppressIfRecent(logger *slog.Logger, p webhooks.NotificationPayload, window time.Duration) bool {
 	k := webhookKey(p)
 	now := time.Now()
 	recentWebhookMu.Lock()
 	defer recentWebhookMu.Unlock()
 	if ts, ok := recentWebhookMap[k]; ok {
 		if now.Sub(ts) < window {
-			log.Printf("[WEBHOOK] Suppression matched for key: %q (last seen: %v ago)", k, now.Sub(ts).Round(time.Millisecond))
+			logger.Info("suppressed duplicate webhook", "key", k, "last_seen", now.Sub(ts).Round(time.Millisecond))
+			metrics.WebhookSuppressed(p.Event)
 			return true
 		}
 	}
 	recentWebhookMap[k] = now
-	log.Printf("[WEBHOOK] No suppression for key: %q", k)
 	return false
 }
 
-func Start(token, guildID string) error {
-	cfg, err := config.Load()
+func Start(cfg config.Config) error {
+	bg, cancel := context.WithCancel(context.Background())
+	stopBackground = cancel
+
+	httpClient := httpx.New(httpx.Options{
+		Timeout:          cfg.Timeouts.HTTP.Duration,
+		Retries:          cfg.HTTP.Retries,
+		RetryBackoff:     cfg.HTTP.RetryBackoff.Duration,
+		RetryMaxBackoff:  cfg.HTTP.RetryMaxBackoff.Duration,
+		MaxConcurrent:    cfg.HTTP.MaxConcurrent,
+		RateLimit:        cfg.HTTP.RateLimit,
+		BreakerThreshold: cfg.HTTP.BreakerThreshold,
+		BreakerCooldown:  cfg.HTTP.BreakerCooldown.Duration,
+	})
+
+	perms, err := permissions.New(cfg, commands.Names())
 	if err != nil {
-		return fmt.Errorf("config load: %w", err)
+		return err
 	}
 
-	httpClient := httpx.New()
+	lim, err := limits.New(cfg.Limits, commands.Names())
+	if err != nil {
+		return err
+	}
 
 	ctx := &appctx.Context{
 		Config: cfg,
 		HTTP:   httpClient,
+		Perms:  perms,
+		Limits: lim,
+		Log:    slog.Default(),
+	}
+
+	httpClient.Name(cfg.Jellyseerr.URL, "jellyseerr")
+	httpClient.Name(cfg.Radarr.URL, "radarr")
+	httpClient.Name(cfg.Sonarr.URL, "sonarr")
+	httpClient.Name(cfg.Tautulli.URL, "tautulli")
+	if cfg.Sessions.File != "" {
+		db, err := session.Open(cfg.Sessions.File)
+		if err != nil {
+			return err
+		}
+		sessionDB = db
+		commands.UseSessionDB(db)
+	}
+	for store, count := range commands.SessionCounts() {
+		metrics.SessionGauge(store, count)
 	}
 
-	if cfg.JellyseerrURL != "" && cfg.JellyseerrAPIKey != "" {
-		ctx.Jelly = jellyseerr.New(cfg.JellyseerrURL, cfg.JellyseerrAPIKey, httpClient)
+	if cfg.Jellyseerr.IsEnabled() {
+		ctx.Jelly = jellyseerr.New(cfg.Jellyseerr.URL, cfg.Jellyseerr.APIKey, httpClient)
+		ctx.Jelly.Users = jellyseerr.NewUserIndex(ctx.Jelly, cfg.Cache.UserIndexFile)
+		go ctx.Jelly.Users.Run(bg, cfg.Cache.UserIndexRefresh.Duration)
 	}
-	if cfg.TautulliURL != "" && cfg.TautulliAPIKey != "" {
-		ctx.Tautulli = tautulli.New(cfg.TautulliURL, cfg.TautulliAPIKey, httpClient)
+	if cfg.Tautulli.IsEnabled() {
+		ctx.Tautulli = tautulli.New(cfg.Tautulli.URL, cfg.Tautulli.APIKey, httpClient)
 	}
-	if cfg.SonarrURL != "" && cfg.SonarrAPIKey != "" {
-		ctx.Sonarr = sonarr.New(cfg.SonarrURL, cfg.SonarrAPIKey, httpClient)
+	if cfg.Sonarr.IsEnabled() {
+		ctx.Sonarr = sonarr.New(cfg.Sonarr.URL, cfg.Sonarr.APIKey, httpClient)
 	}
-	if cfg.RadarrURL != "" && cfg.RadarrAPIKey != "" {
-		ctx.Radarr = radarr.New(cfg.RadarrURL, cfg.RadarrAPIKey, httpClient)
+	if cfg.Radarr.IsEnabled() {
+		ctx.Radarr = radarr.New(cfg.Radarr.URL, cfg.Radarr.APIKey, httpClient)
 	}
 
-	s, err := discordgo.New("Bot " + token)
+	s, err := discordgo.New("Bot " + cfg.Discord.Token)
 	if err != nil {
 		return fmt.Errorf("discord session: %w", err)
 	}
@@ -105,35 +160,62 @@ func Start(token, guildID string) error {
 
 	Session = s
 
-	if err := commands.RegisterAll(Session, guildID); err != nil {
+	if err := commands.RegisterAll(Session, cfg.Discord.GuildID); err != nil {
 		_ = Session.Close()
 		Session = nil
 		return fmt.Errorf("register commands: %w", err)
 	}
 
+	go session.RunJanitor(bg, sessionSweepInterval, commands.WatchSessions(Session)...)
+
+	// Probes for container orchestration, served next to the webhook and on
+	// the metrics listener, so either server can be probed on its own.
+	probes := []webhooks.Route{
+		{Path: "/healthz", Handler: health.Handler(func() []health.Check {
+			return []health.Check{health.Gateway(func() *discordgo.Session { return Session })}
+		}, healthCheckTimeout)},
+		{Path: "/readyz", Handler: health.Handler(func() []health.Check {
+			return health.Upstreams(ctx)
+		}, healthCheckTimeout)},
+	}
+	routes := probes
+	if cfg.Metrics.Enabled {
+		if cfg.Metrics.Addr != "" {
+			mux := http.NewServeMux()
+			mux.Handle(cfg.Metrics.Path, metrics.Handler())
+			for _, r := range probes {
+				mux.Handle(r.Path, r.Handler)
+			}
+			httpServers = append(httpServers, webhooks.Serve(cfg.Metrics.Addr, mux))
+			slog.Info("metrics listening", "addr", cfg.Metrics.Addr, "path", cfg.Metrics.Path)
+		} else {
+			routes = append(routes, webhooks.Route{Path: cfg.Metrics.Path, Handler: metrics.Handler()})
+			slog.Info("metrics served on webhook server", "path", cfg.Metrics.Path)
+		}
+	}
+
 	// Optionally start webhook server
-	if cfg.WebhookAddr != "" && cfg.WebhookPath != "" {
-		server, err := webhooks.Start(cfg.WebhookAddr, cfg.WebhookPath, cfg.WebhookAuthToken, func(p webhooks.NotificationPayload) {
-			log.Printf("[WEBHOOK] Received payload. Event: %q, Subject: %q, ChannelID: %q", p.Event, p.Subject, p.DiscordChannelID)
+	if cfg.Webhook.IsEnabled() {
+		server, err := webhooks.Start(cfg.Webhook.Addr, cfg.Webhook.Path, cfg.Webhook.AuthToken, func(wctx context.Context, p webhooks.NotificationPayload) {
+			logger := logging.FromContext(wctx).With("event", p.Event)
+			logger.Info("webhook received", "subject", p.Subject, "channel", p.DiscordChannelID)
+			metrics.WebhookEvent(p.Event)
 			if Session == nil {
-				log.Printf("[WEBHOOK] Session is nil, skipping")
+				logger.Warn("discord session not ready, dropping webhook")
 				return
 			}
-			channelID := cfg.DiscordChannelID
+			channelID := cfg.Channels.ForEvent(p.Event)
 			if p.DiscordChannelID != "" {
 				channelID = p.DiscordChannelID
 			}
-			log.Printf("[WEBHOOK] Target ChannelID: %q", channelID)
 			if channelID != "" {
-				// De-duplicate bursts of identical webhooks (same event/media) for 30s
-				if suppressIfRecent(p, 30*time.Second) {
-					log.Printf("[WEBHOOK] Suppressed duplicate notification: %s", webhookKey(p))
+				// De-duplicate bursts of identical webhooks (same event/media)
+				if suppressIfRecent(logger, p, cfg.Timeouts.WebhookDedupe.Duration) {
 					return
 				}
 
 				// Create the embed
 				embed := ui.WebhookNotificationEmbed(p)
-				log.Printf("[WEBHOOK] Embed created. Title: %q, Color: %x", embed.Title, embed.Color)
 
 				// If we can resolve the requester Discord user to a Jellyseerr user, fetch
 				// their total request count from Jellyseerr and display it in the embed.
@@ -174,13 +256,13 @@ func Start(token, guildID string) error {
 						}
 
 						if discordID != "" {
-							if jid, err := ctx.Jelly.DiscordUserToJellyseerrUserID(context.Background(), discordID); err == nil && jid > 0 {
+							if jid, err := ctx.Jelly.DiscordUserToJellyseerrUserID(wctx, discordID); err == nil && jid > 0 {
 								jellyID = jid
 							}
 						}
 
 						if jellyID > 0 {
-							if total, err := ctx.Jelly.GetUserRequestTotal(context.Background(), jellyID); err == nil {
+							if total, err := ctx.Jelly.GetUserRequestTotal(wctx, jellyID); err == nil {
 								totalCount = fmt.Sprintf("%d", total)
 							}
 						}
@@ -228,12 +310,12 @@ func Start(token, guildID string) error {
 					}
 					mediaID, _ := strconv.Atoi(mediaIDStr)
 					if mediaID > 0 {
-						detail, err := ctx.Jelly.GetDetail(context.Background(), p.Media.MediaType, mediaID)
+						detail, err := ctx.Jelly.GetDetail(wctx, p.Media.MediaType, mediaID)
 						if err == nil {
 							for _, req := range detail.MediaInfo.Requests {
-								user, err := ctx.Jelly.GetUserDetail(context.Background(), req.RequestedBy.ID)
-								if err == nil && user.Settings.DiscordID != "" {
-									pingIDs[user.Settings.DiscordID] = struct{}{}
+								discordID, err := ctx.Jelly.JellyseerrUserToDiscordID(wctx, req.RequestedBy.ID)
+								if err == nil && discordID != "" {
+									pingIDs[discordID] = struct{}{}
 								}
 							}
 
@@ -273,34 +355,44 @@ func Start(token, guildID string) error {
 					content = strings.Join(pings, " ")
 				}
 
-				_, _ = Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+				if _, err := Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
 					Content: content,
 					Embed:   embed,
-				})
+				}); err != nil {
+					logger.Error("posting webhook notification failed", "channel", channelID, "err", err)
+					return
+				}
+				logger.Info("webhook posted", "channel", channelID, "pings", len(pingIDs))
 			}
-		})
+		}, routes...)
 		if err != nil {
 			_ = Session.Close()
 			Session = nil
 			return fmt.Errorf("start webhook server: %w", err)
 		}
-		webhookServerStop = func() {
-			_ = server.Shutdown(context.Background())
-		}
-		log.Printf("Webhook listening on %s%s", cfg.WebhookAddr, cfg.WebhookPath)
+		httpServers = append(httpServers, server)
+		slog.Info("webhook server listening", "addr", cfg.Webhook.Addr, "path", cfg.Webhook.Path)
 	}
 
-	log.Println("Bot running as:", s.State.User.Username)
+	slog.Info("bot running", "user", s.State.User.Username)
 	return nil
 }
 
 func Stop() {
-	if webhookServerStop != nil {
-		webhookServerStop()
-		webhookServerStop = nil
+	if stopBackground != nil {
+		stopBackground()
+		stopBackground = nil
 	}
+	for _, srv := range httpServers {
+		_ = srv.Shutdown(context.Background())
+	}
+	httpServers = nil
 	if Session != nil {
 		_ = Session.Close()
 		Session = nil
 	}
+	if sessionDB != nil {
+		_ = sessionDB.Close()
+		sessionDB = nil
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/KevinHaeusler/go-haruki/bot/httpx"
@@ -13,6 +14,9 @@ type Client struct {
 	BaseURL string
 	APIKey  string
 	HTTP    *httpx.Client
+
+	// Users, when set, answers Discord <-> Jellyseerr user lookups from memory.
+	Users *UserIndex
 }
 
 func New(baseURL, apiKey string, http *httpx.Client) *Client {
@@ -34,63 +38,106 @@ type MediaSummary struct {
 	Title     string
 	Year      string
 	MediaType string
+	Status    int // media status, 0 when Jellyseerr doesn't know the title yet
 }
 
 type searchResp struct {
-	Results []struct {
-		ID          int    `json:"id"`
-		MediaType   string `json:"mediaType"`
-		Title       string `json:"title"`
-		Name        string `json:"name"`
-		ReleaseDate string `json:"releaseDate"`
-		FirstAir    string `json:"firstAirDate"`
-	} `json:"results"`
+	Page         int          `json:"page"`
+	TotalPages   int          `json:"totalPages"`
+	TotalResults int          `json:"totalResults"`
+	Results      []searchItem `json:"results"`
+}
+
+type searchItem struct {
+	ID          int    `json:"id"`
+	MediaType   string `json:"mediaType"`
+	Title       string `json:"title"`
+	Name        string `json:"name"`
+	ReleaseDate string `json:"releaseDate"`
+	FirstAir    string `json:"firstAirDate"`
+	// only used by discover
+	Overview    string  `json:"overview"`
+	PosterPath  string  `json:"posterPath"`
+	VoteAverage float64 `json:"voteAverage"`
+	MediaInfo   *struct {
+		Status int `json:"status"`
+	} `json:"mediaInfo"`
 }
 
+// SearchPage is one page of search results. Jellyseerr pages hold 20 entries
+// of every type, so a page filtered to one media type can hold fewer, or none.
+type SearchPage struct {
+	Results    []MediaSummary
+	Page       int
+	TotalPages int
+}
+
+// SearchSummary returns the first page of search results.
 func (c *Client) SearchSummary(ctx context.Context, query, mediaType string) ([]MediaSummary, error) {
+	p, err := c.Search(ctx, query, mediaType, 1)
+	return p.Results, err
+}
+
+// Search returns one page of search results, limited to mediaType unless
+// it's empty.
+func (c *Client) Search(ctx context.Context, query, mediaType string, page int) (SearchPage, error) {
 	escaped := url.QueryEscape(query)
 	// Force spaces to %20 instead of +
 	escaped = strings.ReplaceAll(escaped, "+", "%20")
 
-	u := fmt.Sprintf("%s/api/v1/search?query=%s", c.BaseURL, escaped)
+	u := fmt.Sprintf("%s/api/v1/search?query=%s&page=%d", c.BaseURL, escaped, max(page, 1))
 
 	var out searchResp
 	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
-		return nil, err
+		return SearchPage{}, err
 	}
 
 	res := make([]MediaSummary, 0, len(out.Results))
 	for _, r := range out.Results {
-		mt := r.MediaType
-		if mt == "" {
-			mt = mediaType
-		}
-		if mediaType != "" && mt != mediaType {
-			continue
+		if m, ok := r.summary(mediaType); ok {
+			res = append(res, m)
 		}
+	}
 
-		title := r.Title
-		if mt == "tv" && title == "" {
-			title = r.Name
-		}
-		year := ""
-		d := r.ReleaseDate
-		if mt == "tv" && d == "" {
-			d = r.FirstAir
-		}
-		if len(d) >= 4 {
-			year = d[:4]
-		}
+	return SearchPage{Results: res, Page: out.Page, TotalPages: out.TotalPages}, nil
+}
+
+// summary converts a result, taking mediaType for results that don't say. ok
+// is false when mediaType is set and the result is of another type.
+func (r searchItem) summary(mediaType string) (m MediaSummary, ok bool) {
+	mt := r.MediaType
+	if mt == "" {
+		mt = mediaType
+	}
+	if mediaType != "" && mt != mediaType {
+		return MediaSummary{}, false
+	}
+
+	title := r.Title
+	if mt == "tv" && title == "" {
+		title = r.Name
+	}
+	year := ""
+	d := r.ReleaseDate
+	if mt == "tv" && d == "" {
+		d = r.FirstAir
+	}
+	if len(d) >= 4 {
+		year = d[:4]
+	}
 
-		res = append(res, MediaSummary{
-			ID:        r.ID,
-			Title:     title,
-			Year:      year,
-			MediaType: mt,
-		})
+	status := 0
+	if r.MediaInfo != nil {
+		status = r.MediaInfo.Status
 	}
 
-	return res, nil
+	return MediaSummary{
+		ID:        r.ID,
+		Title:     title,
+		Year:      year,
+		MediaType: mt,
+		Status:    status,
+	}, true
 }
 
 /* ---------- Detail (includes mediaInfo + requests) ---------- */
@@ -104,17 +151,47 @@ type MediaDetail struct {
 	FirstAir    string    `json:"firstAirDate"`
 	PosterPath  string    `json:"posterPath"`
 	MediaInfo   MediaInfo `json:"mediaInfo"`
+
+	// Seasons is only set for TV.
+	Seasons []Season `json:"seasons"`
+}
+
+type Season struct {
+	ID           int    `json:"id"`
+	SeasonNumber int    `json:"seasonNumber"`
+	Name         string `json:"name"`
+	EpisodeCount int    `json:"episodeCount"`
+	AirDate      string `json:"airDate"`
 }
 
 type MediaInfo struct {
 	Status   int `json:"status"`
+	Status4k int `json:"status4k"` // same scale as Status, for the 4K servers
 	Requests []struct {
+		Status      int  `json:"status"` // 1 pending, 2 approved, 3 declined
+		Is4k        bool `json:"is4k"`
 		RequestedBy struct {
 			ID          int    `json:"id"`
 			DisplayName string `json:"displayName"`
 			Email       string `json:"email"`
 		} `json:"requestedBy"`
+		Seasons []struct {
+			SeasonNumber int `json:"seasonNumber"`
+		} `json:"seasons"`
 	} `json:"requests"`
+	Seasons []struct {
+		SeasonNumber int `json:"seasonNumber"`
+		Status       int `json:"status"`
+		Status4k     int `json:"status4k"`
+	} `json:"seasons"`
+}
+
+// StatusFor returns Status, or Status4k for the 4K tier.
+func (m MediaInfo) StatusFor(is4k bool) int {
+	if is4k {
+		return m.Status4k
+	}
+	return m.Status
 }
 
 func (d MediaDetail) DisplayTitle(mediaType string) string {
@@ -147,6 +224,58 @@ func (d MediaDetail) HasRequester(userID int) bool {
 	return false
 }
 
+// HasTierRequester reports whether the user has a request for the standard
+// or the 4K tier.
+func (d MediaDetail) HasTierRequester(userID int, is4k bool) bool {
+	for _, r := range d.MediaInfo.Requests {
+		if r.RequestedBy.ID == userID && r.Is4k == is4k {
+			return true
+		}
+	}
+	return false
+}
+
+// SeasonStatus returns the status of every TV season that can't be requested
+// any more in the given tier: 5 available, 4 partially available, 3
+// processing, 2 requested.
+func (d MediaDetail) SeasonStatus(is4k bool) map[int]int {
+	out := make(map[int]int)
+	for _, s := range d.MediaInfo.Seasons {
+		status := s.Status
+		if is4k {
+			status = s.Status4k
+		}
+		if status >= 2 {
+			out[s.SeasonNumber] = status
+		}
+	}
+	for _, r := range d.MediaInfo.Requests {
+		if r.Status == 3 || r.Is4k != is4k {
+			continue // declined, or the other tier
+		}
+		for _, s := range r.Seasons {
+			if _, ok := out[s.SeasonNumber]; !ok {
+				out[s.SeasonNumber] = 2
+			}
+		}
+	}
+	return out
+}
+
+// RequestableSeasons returns the regular seasons (no specials) that are
+// neither available nor requested yet in the given tier, in order.
+func (d MediaDetail) RequestableSeasons(is4k bool) []Season {
+	status := d.SeasonStatus(is4k)
+	out := make([]Season, 0, len(d.Seasons))
+	for _, s := range d.Seasons {
+		if _, taken := status[s.SeasonNumber]; s.SeasonNumber > 0 && !taken {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].SeasonNumber < out[b].SeasonNumber })
+	return out
+}
+
 func (c *Client) GetDetail(ctx context.Context, mediaType string, id int) (MediaDetail, error) {
 	// Python did: GET f"{media_type}/{id}" with language param
 	u := fmt.Sprintf("%s/api/v1/%s/%d?language=en", c.BaseURL, mediaType, id)
@@ -2,35 +2,78 @@ package jellyseerr
 
 import (
 	"context"
+
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
 )
 
+// DiscordUserToJellyseerrUserID returns the Jellyseerr user whose settings
+// hold discordUserID, or 0 if nobody is linked. With a UserIndex attached
+// this is a map lookup; otherwise it scans every user.
 func (c *Client) DiscordUserToJellyseerrUserID(ctx context.Context, discordUserID string) (int, error) {
+	if c.Users != nil {
+		return c.Users.JellyseerrID(ctx, discordUserID)
+	}
+
+	links, _, err := c.scanDiscordLinks(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for jellyID, discordID := range links {
+		if discordID == discordUserID {
+			return jellyID, nil
+		}
+	}
+	return 0, nil
+}
+
+// JellyseerrUserToDiscordID returns the Discord ID linked to a Jellyseerr
+// user, or "" if the user has none.
+func (c *Client) JellyseerrUserToDiscordID(ctx context.Context, jellyUserID int) (string, error) {
+	if c.Users != nil {
+		return c.Users.DiscordID(ctx, jellyUserID)
+	}
+
+	detail, err := c.GetUserDetail(ctx, jellyUserID)
+	if err != nil {
+		return "", err
+	}
+	return detail.Settings.DiscordID, nil
+}
+
+// scanDiscordLinks pages through all users and reads each user's settings,
+// returning jellyseerr user id -> discord id for every linked user. This is
+// one request per user, so callers should go through the UserIndex. Users
+// whose settings fail to load are logged and returned in skipped, so the
+// caller can keep what it knew about them.
+func (c *Client) scanDiscordLinks(ctx context.Context) (links map[int]string, skipped []int, err error) {
 	const take = 100
+	links = make(map[int]string)
 
 	for skip := 0; skip < 2000; skip += take { // safety cap
 		results, total, err := c.ListUsers(ctx, take, skip)
 		if err != nil {
-			return 0, err
+			return nil, nil, err
 		}
 		if len(results) == 0 {
-			return 0, nil
+			break
 		}
 
 		for _, u := range results {
 			detail, err := c.GetUserDetail(ctx, u.ID)
 			if err != nil {
+				logging.FromContext(ctx).Warn("skipping user in Discord link scan", "jelly_user", u.ID, "err", err)
+				skipped = append(skipped, u.ID)
 				continue
 			}
-
-			if detail.Settings.DiscordID == discordUserID {
-				return detail.ID, nil
+			if detail.Settings.DiscordID != "" {
+				links[detail.ID] = detail.Settings.DiscordID
 			}
 		}
 
 		if len(results) < take || skip+take >= total {
-			return 0, nil
+			break
 		}
 	}
 
-	return 0, nil
+	return links, skipped, nil
 }
@@ -0,0 +1,131 @@
+package jellyseerr
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Discover lists, as paths below /api/v1/discover. The genre, studio and
+// network lists take an ID.
+const (
+	DiscoverTrending       = "trending"
+	DiscoverPopularMovies  = "movies"
+	DiscoverPopularTV      = "tv"
+	DiscoverUpcomingMovies = "movies/upcoming"
+	DiscoverUpcomingTV     = "tv/upcoming"
+	DiscoverMovieGenre     = "movies/genre"
+	DiscoverTVGenre        = "tv/genre"
+	DiscoverStudio         = "movies/studio"
+	DiscoverNetwork        = "tv/network"
+)
+
+// DiscoverItem is a movie or show on a discover list.
+type DiscoverItem struct {
+	MediaSummary
+	Overview   string
+	PosterPath string
+	Rating     float64 // TMDB vote average, 0-10
+}
+
+// DiscoverPage is one page of a discover list. People on the trending list
+// are left out, so a page can hold fewer than 20 entries.
+type DiscoverPage struct {
+	Results    []DiscoverItem
+	Page       int
+	TotalPages int
+	// Name of the genre, studio or network the list is for, if any.
+	Name string
+}
+
+type discoverResp struct {
+	Page       int          `json:"page"`
+	TotalPages int          `json:"totalPages"`
+	Results    []searchItem `json:"results"`
+
+	Genre   *struct{ Name string } `json:"genre"`
+	Studio  *struct{ Name string } `json:"studio"`
+	Network *struct{ Name string } `json:"network"`
+}
+
+// Discover returns one page of a discover list. id is the genre, studio or
+// network for the lists that need one and ignored otherwise.
+func (c *Client) Discover(ctx context.Context, list string, id, page int) (DiscoverPage, error) {
+	path := list
+	if list == DiscoverMovieGenre || list == DiscoverTVGenre || list == DiscoverStudio || list == DiscoverNetwork {
+		path = fmt.Sprintf("%s/%d", list, id)
+	}
+	u := fmt.Sprintf("%s/api/v1/discover/%s?page=%d", c.BaseURL, path, max(page, 1))
+
+	var out discoverResp
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return DiscoverPage{}, err
+	}
+
+	// the movie and TV lists don't always say what their entries are
+	mediaType := ""
+	switch {
+	case strings.HasPrefix(list, "movies"):
+		mediaType = "movie"
+	case strings.HasPrefix(list, "tv"):
+		mediaType = "tv"
+	}
+
+	res := DiscoverPage{Page: out.Page, TotalPages: out.TotalPages}
+	for _, r := range out.Results {
+		m, ok := r.summary(mediaType)
+		if !ok || (m.MediaType != "movie" && m.MediaType != "tv") {
+			continue
+		}
+		res.Results = append(res.Results, DiscoverItem{
+			MediaSummary: m,
+			Overview:     r.Overview,
+			PosterPath:   r.PosterPath,
+			Rating:       r.VoteAverage,
+		})
+	}
+	for _, named := range []*struct{ Name string }{out.Genre, out.Studio, out.Network} {
+		if named != nil {
+			res.Name = named.Name
+		}
+	}
+	return res, nil
+}
+
+// Genre is a TMDB genre; movies and TV have separate lists.
+type Genre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ListGenres returns the genres for "movie" or "tv".
+func (c *Client) ListGenres(ctx context.Context, mediaType string) ([]Genre, error) {
+	u := fmt.Sprintf("%s/api/v1/genres/%s", c.BaseURL, mediaType)
+
+	var out []Genre
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Company is a TMDB production company (studio).
+type Company struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// SearchCompanies returns the first page of studios matching query.
+func (c *Client) SearchCompanies(ctx context.Context, query string) ([]Company, error) {
+	escaped := strings.ReplaceAll(url.QueryEscape(query), "+", "%20")
+	u := fmt.Sprintf("%s/api/v1/search/company?query=%s&page=1", c.BaseURL, escaped)
+
+	var out struct {
+		Results []Company `json:"results"`
+	}
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Results, nil
+}
@@ -0,0 +1,40 @@
+package jellyseerr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Quota is a user's request allowance for one media type. TV quotas count
+// seasons, not shows. Limit 0 means unlimited.
+type Quota struct {
+	Days       int  `json:"days"` // rolling window the limit applies to
+	Limit      int  `json:"limit"`
+	Used       int  `json:"used"`
+	Remaining  int  `json:"remaining"`
+	Restricted bool `json:"restricted"` // no requests left
+}
+
+type UserQuota struct {
+	Movie Quota `json:"movie"`
+	TV    Quota `json:"tv"`
+}
+
+// For returns the quota for "movie" or "tv".
+func (q UserQuota) For(mediaType string) Quota {
+	if mediaType == "tv" {
+		return q.TV
+	}
+	return q.Movie
+}
+
+// GetUserQuota returns the movie and TV request quota of a Jellyseerr user.
+func (c *Client) GetUserQuota(ctx context.Context, userID int) (UserQuota, error) {
+	u := fmt.Sprintf("%s/api/v1/user/%d/quota", c.BaseURL, userID)
+
+	var out UserQuota
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
@@ -2,9 +2,17 @@ package jellyseerr
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/KevinHaeusler/go-haruki/bot/httpx"
 )
 
+// ErrQuotaExceeded is returned by RequestMedia when the user has no requests
+// left in their quota.
+var ErrQuotaExceeded = errors.New("request quota exceeded")
+
 // RequestMediaResponse is the response shape we need (requestCount).
 type RequestMediaResponse struct {
 	RequestedBy struct {
@@ -21,26 +29,66 @@ type requestMediaPayload struct {
 	// "Cannot read properties of undefined (reading 'filter')"
 	// Common accepted values: "all" or []int (season numbers).
 	Seasons any `json:"seasons,omitempty"`
+
+	Is4k bool `json:"is4k,omitempty"`
+
+	// Advanced options; Jellyseerr uses the server's defaults when unset.
+	// serverId 0 is a valid ID, hence the pointer.
+	ServerID          *int   `json:"serverId,omitempty"`
+	ProfileID         int    `json:"profileId,omitempty"`
+	RootFolder        string `json:"rootFolder,omitempty"`
+	LanguageProfileID int    `json:"languageProfileId,omitempty"`
+}
+
+// RequestOptions tunes a request. The zero value requests everything with
+// Jellyseerr's defaults.
+type RequestOptions struct {
+	// Seasons lists the TV season numbers to request; empty requests all.
+	Seasons []int
+	// Is4k sends the request to the 4K servers.
+	Is4k bool
+
+	// ServerID picks the Radarr/Sonarr server; nil uses the default one.
+	// The other fields are only used together with a server.
+	ServerID          *int
+	ProfileID         int
+	RootFolder        string
+	LanguageProfileID int
 }
 
 // RequestMedia sends a request to Jellyseerr/Overseerr.
-// For TV, it defaults to requesting all seasons.
-func (c *Client) RequestMedia(ctx context.Context, mediaType string, mediaID int, userID int) (RequestMediaResponse, error) {
+// For TV, it requests opts.Seasons, or all seasons if none are given.
+func (c *Client) RequestMedia(ctx context.Context, mediaType string, mediaID int, userID int, opts RequestOptions) (RequestMediaResponse, error) {
 	u := fmt.Sprintf("%s/api/v1/request", c.BaseURL)
 
 	body := requestMediaPayload{
 		MediaType: mediaType,
 		MediaID:   mediaID,
 		UserID:    userID,
+		Is4k:      opts.Is4k,
+	}
+	if opts.ServerID != nil {
+		body.ServerID = opts.ServerID
+		body.ProfileID = opts.ProfileID
+		body.RootFolder = opts.RootFolder
+		body.LanguageProfileID = opts.LanguageProfileID
 	}
 
 	// ✅ Fix for Jellyseerr TV requests
 	if mediaType == "tv" {
 		body.Seasons = "all"
+		if len(opts.Seasons) > 0 {
+			body.Seasons = opts.Seasons
+		}
 	}
 
 	var out RequestMediaResponse
 	if err := c.HTTP.DoJSON(ctx, "POST", u, c.headers(), body, &out); err != nil {
+		// Jellyseerr answers 403 "Movie Quota exceeded." / "Series Quota exceeded."
+		var se *httpx.StatusError
+		if errors.As(err, &se) && se.StatusCode == 403 && strings.Contains(strings.ToLower(se.Body), "quota") {
+			return out, fmt.Errorf("%w: %w", ErrQuotaExceeded, err)
+		}
 		return out, err
 	}
 	return out, nil
@@ -0,0 +1,67 @@
+package jellyseerr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Server is a Radarr or Sonarr server configured in Jellyseerr.
+type Server struct {
+	ID                      int    `json:"id"`
+	Name                    string `json:"name"`
+	Is4k                    bool   `json:"is4k"`
+	IsDefault               bool   `json:"isDefault"`
+	ActiveDirectory         string `json:"activeDirectory"`
+	ActiveProfileID         int    `json:"activeProfileId"`
+	ActiveLanguageProfileID int    `json:"activeLanguageProfileId"`
+}
+
+type QualityProfile struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type RootFolder struct {
+	ID        int    `json:"id"`
+	Path      string `json:"path"`
+	FreeSpace int64  `json:"freeSpace"`
+}
+
+// ServerOptions is what a request to one server can choose from.
+type ServerOptions struct {
+	Server      Server           `json:"server"`
+	Profiles    []QualityProfile `json:"profiles"`
+	RootFolders []RootFolder     `json:"rootFolders"`
+	// LanguageProfiles is only set for Sonarr v3.
+	LanguageProfiles []QualityProfile `json:"languageProfiles"`
+}
+
+// serviceFor returns the Jellyseerr service that handles a media type.
+func serviceFor(mediaType string) string {
+	if mediaType == "tv" {
+		return "sonarr"
+	}
+	return "radarr"
+}
+
+// ListServers returns the Radarr (movie) or Sonarr (tv) servers.
+func (c *Client) ListServers(ctx context.Context, mediaType string) ([]Server, error) {
+	u := fmt.Sprintf("%s/api/v1/service/%s", c.BaseURL, serviceFor(mediaType))
+
+	var out []Server
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetServerOptions returns the profiles and root folders of one server.
+func (c *Client) GetServerOptions(ctx context.Context, mediaType string, serverID int) (ServerOptions, error) {
+	u := fmt.Sprintf("%s/api/v1/service/%s/%d", c.BaseURL, serviceFor(mediaType), serverID)
+
+	var out ServerOptions
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
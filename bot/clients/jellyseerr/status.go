@@ -0,0 +1,23 @@
+package jellyseerr
+
+import (
+	"context"
+	"fmt"
+)
+
+type Status struct {
+	Version         string `json:"version"`
+	CommitTag       string `json:"commitTag"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+}
+
+// GetStatus returns the Jellyseerr version; it doubles as a reachability check.
+func (c *Client) GetStatus(ctx context.Context) (*Status, error) {
+	u := fmt.Sprintf("%s/api/v1/status", c.BaseURL)
+
+	var out Status
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
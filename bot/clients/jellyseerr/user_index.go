@@ -0,0 +1,273 @@
+package jellyseerr
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
+)
+
+// userIndexMissRefresh is how old the index must be before a lookup miss
+// triggers a rebuild, so users linked in the Jellyseerr UI are picked up
+// without rebuilding on every unlinked user.
+const userIndexMissRefresh = time.Minute
+
+// userIndexRefreshTimeout bounds a rebuild started by a lookup miss, which
+// outlives the lookup's own context.
+const userIndexRefreshTimeout = 5 * time.Minute
+
+// UserIndex is an in-memory, bidirectional map of Discord ID <-> Jellyseerr
+// user ID. It is built once from the user list, refreshed in the background
+// and updated immediately when the bot links a user itself. When path is
+// set, the index is persisted there so restarts start warm.
+type UserIndex struct {
+	client *Client
+	path   string
+
+	mu        sync.RWMutex
+	byDiscord map[string]int
+	byJelly   map[int]string
+	builtAt   time.Time
+	missedAt  time.Time // last miss that started a rebuild
+
+	// gen counts Set calls; recent holds each user's last Set so a rebuild
+	// that scanned before it doesn't undo it.
+	gen    uint64
+	recent map[int]recentSet
+
+	buildMu    sync.Mutex
+	refreshing atomic.Bool
+}
+
+type recentSet struct {
+	discordID string // empty for an unlink
+	gen       uint64
+}
+
+type userIndexSnapshot struct {
+	BuiltAt time.Time      `json:"builtAt"`
+	Links   map[int]string `json:"links"` // jellyseerr user id -> discord id
+}
+
+func NewUserIndex(c *Client, path string) *UserIndex {
+	x := &UserIndex{
+		client:    c,
+		path:      path,
+		byDiscord: make(map[string]int),
+		byJelly:   make(map[int]string),
+		recent:    make(map[int]recentSet),
+	}
+	x.load()
+	return x
+}
+
+// Run rebuilds the index every interval until ctx is done.
+func (x *UserIndex) Run(ctx context.Context, interval time.Duration) {
+	if err := x.Refresh(ctx); err != nil {
+		slog.Warn("user index build failed", "err", err)
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := x.Refresh(ctx); err != nil {
+				slog.Warn("user index refresh failed", "err", err)
+			}
+		}
+	}
+}
+
+// Refresh rebuilds the index from Jellyseerr. Concurrent callers share one
+// rebuild. If the rebuild fails the previous index is kept; users the scan
+// had to skip keep their previous link, and links set while it ran win over
+// what it saw.
+func (x *UserIndex) Refresh(ctx context.Context) error {
+	x.mu.RLock()
+	before := x.builtAt
+	x.mu.RUnlock()
+
+	x.buildMu.Lock()
+	defer x.buildMu.Unlock()
+
+	// someone else rebuilt while we waited
+	x.mu.RLock()
+	rebuilt := x.builtAt.After(before)
+	x.mu.RUnlock()
+	if rebuilt {
+		return nil
+	}
+
+	x.mu.RLock()
+	started := x.gen
+	x.mu.RUnlock()
+
+	links, skipped, err := x.client.scanDiscordLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	for _, jellyID := range skipped {
+		if discordID, ok := x.byJelly[jellyID]; ok {
+			links[jellyID] = discordID
+		}
+	}
+	byJelly, byDiscord := make(map[int]string, len(links)), make(map[string]int, len(links))
+	for jellyID, discordID := range links {
+		link(byJelly, byDiscord, jellyID, discordID)
+	}
+	recent := make(map[int]recentSet)
+	for jellyID, r := range x.recent {
+		if r.gen > started {
+			link(byJelly, byDiscord, jellyID, r.discordID)
+			recent[jellyID] = r
+		}
+	}
+	x.byJelly, x.byDiscord, x.recent = byJelly, byDiscord, recent
+	x.builtAt = time.Now()
+	x.mu.Unlock()
+
+	logging.FromContext(ctx).Info("user index built", "linked_users", len(links))
+	x.save()
+	return nil
+}
+
+// JellyseerrID returns the Jellyseerr user linked to discordID, or 0. A miss
+// on an index older than userIndexMissRefresh starts a rebuild in the
+// background; the lookup itself answers from the current index.
+func (x *UserIndex) JellyseerrID(ctx context.Context, discordID string) (int, error) {
+	if err := x.ensureBuilt(ctx); err != nil {
+		return 0, err
+	}
+	x.mu.Lock()
+	id, ok := x.byDiscord[discordID]
+	stale := !ok && time.Since(x.builtAt) > userIndexMissRefresh && time.Since(x.missedAt) > userIndexMissRefresh
+	if stale {
+		x.missedAt = time.Now()
+	}
+	x.mu.Unlock()
+
+	if stale {
+		x.refreshInBackground(ctx)
+	}
+	return id, nil
+}
+
+// DiscordID returns the Discord ID linked to a Jellyseerr user, or "".
+func (x *UserIndex) DiscordID(ctx context.Context, jellyID int) (string, error) {
+	if err := x.ensureBuilt(ctx); err != nil {
+		return "", err
+	}
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.byJelly[jellyID], nil
+}
+
+// Set records that jellyID is now linked to discordID (empty to unlink),
+// replacing any previous link on either side.
+func (x *UserIndex) Set(jellyID int, discordID string) {
+	x.mu.Lock()
+	link(x.byJelly, x.byDiscord, jellyID, discordID)
+	x.gen++
+	x.recent[jellyID] = recentSet{discordID: discordID, gen: x.gen}
+	x.mu.Unlock()
+	x.save()
+}
+
+// link links jellyID to discordID (empty to unlink) in both maps, dropping
+// any previous link on either side.
+func link(byJelly map[int]string, byDiscord map[string]int, jellyID int, discordID string) {
+	if old, ok := byJelly[jellyID]; ok {
+		delete(byDiscord, old)
+		delete(byJelly, jellyID)
+	}
+	if discordID != "" {
+		if old, ok := byDiscord[discordID]; ok {
+			delete(byJelly, old)
+		}
+		byDiscord[discordID] = jellyID
+		byJelly[jellyID] = discordID
+	}
+}
+
+// refreshInBackground rebuilds the index without holding up the caller,
+// unless a background rebuild is already running.
+func (x *UserIndex) refreshInBackground(ctx context.Context) {
+	if !x.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer x.refreshing.Store(false)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), userIndexRefreshTimeout)
+		defer cancel()
+		if err := x.Refresh(ctx); err != nil {
+			logging.FromContext(ctx).Warn("user index refresh failed", "err", err)
+		}
+	}()
+}
+
+func (x *UserIndex) ensureBuilt(ctx context.Context) error {
+	x.mu.RLock()
+	built := !x.builtAt.IsZero()
+	x.mu.RUnlock()
+	if built {
+		return nil
+	}
+	return x.Refresh(ctx)
+}
+
+func (x *UserIndex) load() {
+	if x.path == "" {
+		return
+	}
+	raw, err := os.ReadFile(x.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("user index load failed", "path", x.path, "err", err)
+		}
+		return
+	}
+	var snap userIndexSnapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		slog.Warn("user index load failed", "path", x.path, "err", err)
+		return
+	}
+	for jellyID, discordID := range snap.Links {
+		x.byJelly[jellyID] = discordID
+		x.byDiscord[discordID] = jellyID
+	}
+	x.builtAt = snap.BuiltAt
+}
+
+func (x *UserIndex) save() {
+	if x.path == "" {
+		return
+	}
+	x.mu.RLock()
+	snap := userIndexSnapshot{BuiltAt: x.builtAt, Links: make(map[int]string, len(x.byJelly))}
+	for k, v := range x.byJelly {
+		snap.Links[k] = v
+	}
+	x.mu.RUnlock()
+
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return
+	}
+	tmp := x.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		slog.Warn("user index save failed", "path", x.path, "err", err)
+		return
+	}
+	if err := os.Rename(tmp, x.path); err != nil {
+		slog.Warn("user index save failed", "path", x.path, "err", err)
+	}
+}
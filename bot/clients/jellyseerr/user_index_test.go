@@ -0,0 +1,108 @@
+package jellyseerr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/KevinHaeusler/go-haruki/bot/httpx"
+)
+
+// fakeUsers is a Jellyseerr serving the user list and user details.
+type fakeUsers struct {
+	mu      sync.Mutex
+	discord map[int]string // jellyseerr id -> discord id
+	failing map[int]bool   // user details that answer 500
+	// onDetail, if set, runs before a user detail is served.
+	onDetail func(id int)
+}
+
+func (f *fakeUsers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest, _ := strings.CutPrefix(r.URL.Path, "/api/v1/user")
+	if rest == "" {
+		f.mu.Lock()
+		resp := listUsersResp{Total: len(f.discord)}
+		for id := range f.discord {
+			resp.Results = append(resp.Results, UserSummary{ID: id})
+		}
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	id, _ := strconv.Atoi(strings.TrimPrefix(rest, "/"))
+	f.mu.Lock()
+	onDetail, failing := f.onDetail, f.failing[id]
+	f.mu.Unlock()
+	if onDetail != nil {
+		onDetail(id)
+	}
+	if failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	f.mu.Lock()
+	_, _ = fmt.Fprintf(w, `{"id":%d,"settings":{"discordId":%q}}`, id, f.discord[id])
+	f.mu.Unlock()
+}
+
+func newTestIndex(t *testing.T, f *fakeUsers) *UserIndex {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return NewUserIndex(New(srv.URL, "key", httpx.New(httpx.Options{})), "")
+}
+
+func TestRefreshKeepsSkippedUsers(t *testing.T) {
+	f := &fakeUsers{discord: map[int]string{1: "100", 2: "200"}, failing: map[int]bool{}}
+	x := newTestIndex(t, f)
+	ctx := context.Background()
+	if err := x.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// user 1's details now fail, user 2 unlinked in the Jellyseerr UI
+	f.mu.Lock()
+	f.failing[1] = true
+	f.discord[2] = ""
+	f.mu.Unlock()
+	x.builtAt = x.builtAt.Add(-userIndexMissRefresh) // let Refresh run again
+	if err := x.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() = %v, want the failing user skipped", err)
+	}
+
+	if id, _ := x.JellyseerrID(ctx, "100"); id != 1 {
+		t.Errorf("JellyseerrID(100) = %d, want 1 kept from the previous index", id)
+	}
+	if d, _ := x.DiscordID(ctx, 2); d != "" {
+		t.Errorf("DiscordID(2) = %q, want unlinked", d)
+	}
+}
+
+func TestRefreshKeepsLinksSetDuringScan(t *testing.T) {
+	f := &fakeUsers{discord: map[int]string{1: "", 2: "200"}}
+	x := newTestIndex(t, f)
+
+	// link user 1 while the scan is reading user details
+	var once sync.Once
+	f.onDetail = func(int) {
+		once.Do(func() { x.Set(1, "100") })
+	}
+	ctx := context.Background()
+	if err := x.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, _ := x.JellyseerrID(ctx, "100"); id != 1 {
+		t.Errorf("JellyseerrID(100) = %d, want 1 from the Set during the scan", id)
+	}
+	if id, _ := x.JellyseerrID(ctx, "200"); id != 2 {
+		t.Errorf("JellyseerrID(200) = %d, want 2 from the scan", id)
+	}
+}
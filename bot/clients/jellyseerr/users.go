@@ -192,14 +192,26 @@ func (c *Client) UpdateUserDiscordID(ctx context.Context, jellyUserID int, disco
 
 	// Some servers return the updated settings; we don't need it.
 	var ignore any
-	return c.HTTP.DoJSON(ctx, "PUT", u, c.headers(), body, &ignore)
+	if err := c.HTTP.DoJSON(ctx, "PUT", u, c.headers(), body, &ignore); err != nil {
+		return err
+	}
+	if c.Users != nil {
+		c.Users.Set(jellyUserID, discordID)
+	}
+	return nil
 }
 
 func (c *Client) UpdateUserNotificationSettings(ctx context.Context, jellyUserID int, settings NotificationSettings) error {
 	u := fmt.Sprintf("%s/api/v1/user/%d/settings/notifications", c.BaseURL, jellyUserID)
 
 	var ignore any
-	return c.HTTP.DoJSON(ctx, "POST", u, c.headers(), settings, &ignore)
+	if err := c.HTTP.DoJSON(ctx, "POST", u, c.headers(), settings, &ignore); err != nil {
+		return err
+	}
+	if c.Users != nil && settings.DiscordID != "" {
+		c.Users.Set(jellyUserID, settings.DiscordID)
+	}
+	return nil
 }
 
 func GetUserName(c *Client, id int) (string, error) {
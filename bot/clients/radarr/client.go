@@ -3,6 +3,7 @@ package radarr
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/KevinHaeusler/go-haruki/bot/httpx"
@@ -34,22 +35,101 @@ type SystemStatus struct {
 
 func (c *Client) GetSystemStatus(ctx context.Context) (*SystemStatus, error) {
 	var out SystemStatus
-	url := fmt.Sprintf("%s/api/v3/system/status", c.BaseURL)
-	if err := c.HTTP.DoJSON(ctx, "GET", url, c.headers(), nil, &out); err != nil {
+	if err := c.get(ctx, "system/status", &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
 }
 
+// HealthCheck is one entry of Radarr's System > Status health list.
+type HealthCheck struct {
+	Source  string `json:"source"`
+	Type    string `json:"type"` // ok, notice, warning or error
+	Message string `json:"message"`
+	WikiURL string `json:"wikiUrl"`
+}
+
+// GetHealth returns the problems Radarr currently reports (indexers down,
+// disk space, failed imports...). An empty list means all is well.
+func (c *Client) GetHealth(ctx context.Context) ([]HealthCheck, error) {
+	var out []HealthCheck
+	if err := c.get(ctx, "health", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMovies returns every movie in the Radarr library.
+func (c *Client) ListMovies(ctx context.Context) ([]Movie, error) {
+	var out []Movie
+	if err := c.get(ctx, "movie", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetMovie returns a single movie by its Radarr ID.
+func (c *Client) GetMovie(ctx context.Context, id int) (*Movie, error) {
+	var out Movie
+	if err := c.get(ctx, fmt.Sprintf("movie/%d", id), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMovieFiles returns the files Radarr has on disk for a movie.
+func (c *Client) GetMovieFiles(ctx context.Context, movieID int) ([]MovieFile, error) {
+	var out []MovieFile
+	if err := c.get(ctx, fmt.Sprintf("moviefile?movieId=%d", movieID), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWantedMissing returns one page of monitored movies that have no file yet.
+// Pages are 1-based, as in the Radarr API.
+func (c *Client) GetWantedMissing(ctx context.Context, page, pageSize int) (*Page[Movie], error) {
+	q := url.Values{}
+	q.Set("page", fmt.Sprintf("%d", page))
+	q.Set("pageSize", fmt.Sprintf("%d", pageSize))
+	q.Set("monitored", "true")
+
+	var out Page[Movie]
+	if err := c.get(ctx, "wanted/missing?"+q.Encode(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchReleases runs an interactive indexer search for a movie. This can take
+// a long time, depending on the configured indexers.
+func (c *Client) SearchReleases(ctx context.Context, movieID int) ([]Release, error) {
+	var out []Release
+	if err := c.get(ctx, fmt.Sprintf("release?movieId=%d", movieID), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrabRelease tells Radarr to download a release returned by SearchReleases,
+// identified by its GUID and indexer.
+func (c *Client) GrabRelease(ctx context.Context, guid string, indexerID int) error {
+	body := grabRequest{
+		GUID:      guid,
+		IndexerID: indexerID,
+	}
+	return c.post(ctx, "release", body, nil)
+}
+
 func (c *Client) apiURL(path string) string {
 	p := strings.TrimLeft(path, "/")
 	return fmt.Sprintf("%s/api/v3/%s", c.BaseURL, p)
 }
 
-func (c *Client) Get(ctx context.Context, path string, out any) error {
+func (c *Client) get(ctx context.Context, path string, out any) error {
 	return c.HTTP.DoJSON(ctx, "GET", c.apiURL(path), c.headers(), nil, out)
 }
 
-func (c *Client) Post(ctx context.Context, path string, body any, out any) error {
+func (c *Client) post(ctx context.Context, path string, body any, out any) error {
 	return c.HTTP.DoJSON(ctx, "POST", c.apiURL(path), c.headers(), body, out)
 }
@@ -0,0 +1,108 @@
+package radarr
+
+import "time"
+
+// Page is the paging envelope Radarr wraps list endpoints like wanted/missing in.
+type Page[T any] struct {
+	Page          int    `json:"page"`
+	PageSize      int    `json:"pageSize"`
+	SortKey       string `json:"sortKey"`
+	SortDirection string `json:"sortDirection"`
+	TotalRecords  int    `json:"totalRecords"`
+	Records       []T    `json:"records"`
+}
+
+type Movie struct {
+	ID            int       `json:"id"`
+	Title         string    `json:"title"`
+	OriginalTitle string    `json:"originalTitle"`
+	SortTitle     string    `json:"sortTitle"`
+	Year          int       `json:"year"`
+	Status        string    `json:"status"`
+	Overview      string    `json:"overview"`
+	Monitored     bool      `json:"monitored"`
+	HasFile       bool      `json:"hasFile"`
+	IsAvailable   bool      `json:"isAvailable"`
+	Path          string    `json:"path"`
+	TMDBID        int       `json:"tmdbId"`
+	IMDBID        string    `json:"imdbId"`
+	SizeOnDisk    int64     `json:"sizeOnDisk"`
+	Runtime       int       `json:"runtime"`
+	Added         time.Time `json:"added"`
+}
+
+type MovieFile struct {
+	ID           int          `json:"id"`
+	MovieID      int          `json:"movieId"`
+	RelativePath string       `json:"relativePath"`
+	Path         string       `json:"path"`
+	Size         int64        `json:"size"`
+	DateAdded    time.Time    `json:"dateAdded"`
+	ReleaseGroup string       `json:"releaseGroup"`
+	Quality      QualityModel `json:"quality"`
+	Languages    []Language   `json:"languages"`
+}
+
+type Quality struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Source     string `json:"source"`
+	Resolution int    `json:"resolution"`
+}
+
+type Revision struct {
+	Version  int  `json:"version"`
+	Real     int  `json:"real"`
+	IsRepack bool `json:"isRepack"`
+}
+
+// QualityModel is the quality + revision pair Radarr attaches to files and releases.
+type QualityModel struct {
+	Quality  Quality  `json:"quality"`
+	Revision Revision `json:"revision"`
+}
+
+type Language struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Release is a single result of an interactive indexer search.
+type Release struct {
+	GUID              string       `json:"guid"`
+	Title             string       `json:"title"`
+	MovieTitles       []string     `json:"movieTitles"`
+	Quality           QualityModel `json:"quality"`
+	QualityWeight     int          `json:"qualityWeight"`
+	CustomFormatScore int          `json:"customFormatScore"`
+	Age               int          `json:"age"`
+	Size              int64        `json:"size"`
+	IndexerID         int          `json:"indexerId"`
+	Indexer           string       `json:"indexer"`
+	ReleaseGroup      string       `json:"releaseGroup"`
+	Protocol          string       `json:"protocol"`
+	Seeders           *int         `json:"seeders,omitempty"`
+	Leechers          *int         `json:"leechers,omitempty"`
+	Languages         []Language   `json:"languages"`
+	Approved          bool         `json:"approved"`
+	Rejected          bool         `json:"rejected"`
+	Rejections        []string     `json:"rejections"`
+	PublishDate       time.Time    `json:"publishDate"`
+	InfoURL           string       `json:"infoUrl"`
+}
+
+// DisplayTitle returns the release title, falling back to the parsed movie title.
+func (r Release) DisplayTitle() string {
+	if r.Title != "" {
+		return r.Title
+	}
+	if len(r.MovieTitles) > 0 {
+		return r.MovieTitles[0]
+	}
+	return ""
+}
+
+type grabRequest struct {
+	GUID      string `json:"guid"`
+	IndexerID int    `json:"indexerId"`
+}
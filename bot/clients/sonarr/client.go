@@ -3,6 +3,7 @@ package sonarr
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/KevinHaeusler/go-haruki/bot/httpx"
@@ -34,8 +35,111 @@ type SystemStatus struct {
 
 func (c *Client) GetSystemStatus(ctx context.Context) (*SystemStatus, error) {
 	var out SystemStatus
-	url := fmt.Sprintf("%s/api/v3/system/status", c.BaseURL)
-	if err := c.HTTP.DoJSON(ctx, "GET", url, c.headers(), nil, &out); err != nil {
+	if err := c.get(ctx, "system/status", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HealthCheck is one entry of Sonarr's System > Status health list.
+type HealthCheck struct {
+	Source  string `json:"source"`
+	Type    string `json:"type"` // ok, notice, warning or error
+	Message string `json:"message"`
+	WikiURL string `json:"wikiUrl"`
+}
+
+// GetHealth returns the problems Sonarr currently reports (indexers down,
+// disk space, failed imports...). An empty list means all is well.
+func (c *Client) GetHealth(ctx context.Context) ([]HealthCheck, error) {
+	var out []HealthCheck
+	if err := c.get(ctx, "health", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSeries returns every series in the Sonarr library.
+func (c *Client) ListSeries(ctx context.Context) ([]Series, error) {
+	var out []Series
+	if err := c.get(ctx, "series", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSeries returns a single series by its Sonarr ID.
+func (c *Client) GetSeries(ctx context.Context, id int) (*Series, error) {
+	var out Series
+	if err := c.get(ctx, fmt.Sprintf("series/%d", id), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEpisodes returns every episode of a series, across all seasons.
+func (c *Client) ListEpisodes(ctx context.Context, seriesID int) ([]Episode, error) {
+	var out []Episode
+	if err := c.get(ctx, fmt.Sprintf("episode?seriesId=%d", seriesID), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetEpisodeFiles returns the files Sonarr has on disk for a series.
+func (c *Client) GetEpisodeFiles(ctx context.Context, seriesID int) ([]EpisodeFile, error) {
+	var out []EpisodeFile
+	if err := c.get(ctx, fmt.Sprintf("episodefile?seriesId=%d", seriesID), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchEpisodeReleases runs an interactive indexer search for one episode.
+// This can take a long time, depending on the configured indexers.
+func (c *Client) SearchEpisodeReleases(ctx context.Context, episodeID int) ([]Release, error) {
+	var out []Release
+	if err := c.get(ctx, fmt.Sprintf("release?episodeId=%d", episodeID), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchSeasonReleases runs an interactive indexer search for a whole season,
+// which mostly returns season packs.
+func (c *Client) SearchSeasonReleases(ctx context.Context, seriesID, seasonNumber int) ([]Release, error) {
+	q := url.Values{}
+	q.Set("seriesId", fmt.Sprintf("%d", seriesID))
+	q.Set("seasonNumber", fmt.Sprintf("%d", seasonNumber))
+
+	var out []Release
+	if err := c.get(ctx, "release?"+q.Encode(), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GrabRelease tells Sonarr to download a release returned by one of the
+// release searches, identified by its GUID and indexer.
+func (c *Client) GrabRelease(ctx context.Context, guid string, indexerID int) error {
+	body := grabRequest{
+		GUID:      guid,
+		IndexerID: indexerID,
+	}
+	return c.post(ctx, "release", body, nil)
+}
+
+// RunCommand queues a Sonarr command such as "EpisodeSearch" or "RefreshSeries".
+// body carries the command specific fields; "name" is set from the argument.
+func (c *Client) RunCommand(ctx context.Context, name string, body map[string]any) (*Command, error) {
+	payload := make(map[string]any, len(body)+1)
+	for k, v := range body {
+		payload[k] = v
+	}
+	payload["name"] = name
+
+	var out Command
+	if err := c.post(ctx, "command", payload, &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
@@ -46,10 +150,10 @@ func (c *Client) apiURL(path string) string {
 	return fmt.Sprintf("%s/api/v3/%s", c.BaseURL, p)
 }
 
-func (c *Client) Get(ctx context.Context, path string, out any) error {
+func (c *Client) get(ctx context.Context, path string, out any) error {
 	return c.HTTP.DoJSON(ctx, "GET", c.apiURL(path), c.headers(), nil, out)
 }
 
-func (c *Client) Post(ctx context.Context, path string, body any, out any) error {
+func (c *Client) post(ctx context.Context, path string, body any, out any) error {
 	return c.HTTP.DoJSON(ctx, "POST", c.apiURL(path), c.headers(), body, out)
 }
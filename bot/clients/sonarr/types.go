@@ -0,0 +1,148 @@
+package sonarr
+
+import (
+	"fmt"
+	"time"
+)
+
+type Series struct {
+	ID         int               `json:"id"`
+	Title      string            `json:"title"`
+	SortTitle  string            `json:"sortTitle"`
+	Year       int               `json:"year"`
+	Status     string            `json:"status"`
+	Overview   string            `json:"overview"`
+	Network    string            `json:"network"`
+	Monitored  bool              `json:"monitored"`
+	Path       string            `json:"path"`
+	TVDBID     int               `json:"tvdbId"`
+	TMDBID     int               `json:"tmdbId"`
+	IMDBID     string            `json:"imdbId"`
+	Seasons    []Season          `json:"seasons"`
+	Statistics *SeriesStatistics `json:"statistics,omitempty"`
+	Added      time.Time         `json:"added"`
+}
+
+type Season struct {
+	SeasonNumber int               `json:"seasonNumber"`
+	Monitored    bool              `json:"monitored"`
+	Statistics   *SeasonStatistics `json:"statistics,omitempty"`
+}
+
+// SeasonStatistics are the per-season file counts Sonarr reports on a series.
+type SeasonStatistics struct {
+	EpisodeFileCount  int        `json:"episodeFileCount"`
+	EpisodeCount      int        `json:"episodeCount"`
+	TotalEpisodeCount int        `json:"totalEpisodeCount"`
+	SizeOnDisk        int64      `json:"sizeOnDisk"`
+	PercentOfEpisodes float64    `json:"percentOfEpisodes"`
+	PreviousAiring    *time.Time `json:"previousAiring,omitempty"`
+	NextAiring        *time.Time `json:"nextAiring,omitempty"`
+}
+
+type SeriesStatistics struct {
+	SeasonCount       int     `json:"seasonCount"`
+	EpisodeFileCount  int     `json:"episodeFileCount"`
+	EpisodeCount      int     `json:"episodeCount"`
+	TotalEpisodeCount int     `json:"totalEpisodeCount"`
+	SizeOnDisk        int64   `json:"sizeOnDisk"`
+	PercentOfEpisodes float64 `json:"percentOfEpisodes"`
+}
+
+type Episode struct {
+	ID            int        `json:"id"`
+	SeriesID      int        `json:"seriesId"`
+	EpisodeFileID int        `json:"episodeFileId"`
+	SeasonNumber  int        `json:"seasonNumber"`
+	EpisodeNumber int        `json:"episodeNumber"`
+	Title         string     `json:"title"`
+	AirDate       string     `json:"airDate"`
+	AirDateUTC    *time.Time `json:"airDateUtc,omitempty"`
+	Overview      string     `json:"overview"`
+	HasFile       bool       `json:"hasFile"`
+	Monitored     bool       `json:"monitored"`
+}
+
+// Label returns the "S01E02 - Title" form used in menus and messages.
+func (e Episode) Label() string {
+	return fmt.Sprintf("S%02dE%02d - %s", e.SeasonNumber, e.EpisodeNumber, e.Title)
+}
+
+type EpisodeFile struct {
+	ID           int          `json:"id"`
+	SeriesID     int          `json:"seriesId"`
+	SeasonNumber int          `json:"seasonNumber"`
+	RelativePath string       `json:"relativePath"`
+	Path         string       `json:"path"`
+	Size         int64        `json:"size"`
+	DateAdded    time.Time    `json:"dateAdded"`
+	ReleaseGroup string       `json:"releaseGroup"`
+	Quality      QualityModel `json:"quality"`
+	Languages    []Language   `json:"languages"`
+}
+
+type Quality struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Source     string `json:"source"`
+	Resolution int    `json:"resolution"`
+}
+
+type Revision struct {
+	Version  int  `json:"version"`
+	Real     int  `json:"real"`
+	IsRepack bool `json:"isRepack"`
+}
+
+// QualityModel is the quality + revision pair Sonarr attaches to files and releases.
+type QualityModel struct {
+	Quality  Quality  `json:"quality"`
+	Revision Revision `json:"revision"`
+}
+
+type Language struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Release is a single result of an interactive indexer search.
+type Release struct {
+	GUID              string       `json:"guid"`
+	Title             string       `json:"title"`
+	SeriesTitle       string       `json:"seriesTitle"`
+	SeasonNumber      int          `json:"seasonNumber"`
+	EpisodeNumbers    []int        `json:"episodeNumbers"`
+	FullSeason        bool         `json:"fullSeason"`
+	Quality           QualityModel `json:"quality"`
+	QualityWeight     int          `json:"qualityWeight"`
+	CustomFormatScore int          `json:"customFormatScore"`
+	Age               int          `json:"age"`
+	Size              int64        `json:"size"`
+	IndexerID         int          `json:"indexerId"`
+	Indexer           string       `json:"indexer"`
+	ReleaseGroup      string       `json:"releaseGroup"`
+	Protocol          string       `json:"protocol"`
+	Seeders           *int         `json:"seeders,omitempty"`
+	Leechers          *int         `json:"leechers,omitempty"`
+	Languages         []Language   `json:"languages"`
+	Approved          bool         `json:"approved"`
+	Rejected          bool         `json:"rejected"`
+	Rejections        []string     `json:"rejections"`
+	PublishDate       time.Time    `json:"publishDate"`
+	InfoURL           string       `json:"infoUrl"`
+}
+
+// Command is the status of a queued Sonarr command.
+type Command struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Status  string    `json:"status"`
+	Result  string    `json:"result"`
+	Queued  time.Time `json:"queued"`
+	Message string    `json:"message"`
+}
+
+type grabRequest struct {
+	GUID      string `json:"guid"`
+	IndexerID int    `json:"indexerId"`
+}
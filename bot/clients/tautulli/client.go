@@ -84,20 +84,13 @@ type Session struct {
 }
 
 func (c *Client) GetActivity(ctx context.Context) (*GetActivityResponse, error) {
-	u, err := url.Parse(c.BaseURL)
+	u, err := c.apiURL("get_activity")
 	if err != nil {
 		return nil, err
 	}
-	if !strings.Contains(u.Path, "/api/") {
-		u.Path = strings.TrimRight(u.Path, "/") + "/api/v2"
-	}
-	q := u.Query()
-	q.Set("apikey", c.APIKey)
-	q.Set("cmd", "get_activity")
-	u.RawQuery = q.Encode()
 
 	var out GetActivityResponse
-	if err := c.HTTP.DoJSON(ctx, "GET", u.String(), c.headers(), nil, &out); err != nil {
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
 		return nil, err
 	}
 
@@ -108,6 +101,53 @@ func (c *Client) GetActivity(ctx context.Context) (*GetActivityResponse, error)
 	return &out, nil
 }
 
+type ServerInfo struct {
+	PMSName     string `json:"pms_name"`
+	PMSVersion  string `json:"pms_version"`
+	PMSPlatform string `json:"pms_platform"`
+}
+
+type getServerInfoResponse struct {
+	Response struct {
+		Result  string      `json:"result"`
+		Message interface{} `json:"message"`
+		Data    ServerInfo  `json:"data"`
+	} `json:"response"`
+}
+
+// GetServerInfo returns the Plex server Tautulli is connected to. It fails
+// when Tautulli itself or its Plex connection is down.
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	u, err := c.apiURL("get_server_info")
+	if err != nil {
+		return nil, err
+	}
+
+	var out getServerInfoResponse
+	if err := c.HTTP.DoJSON(ctx, "GET", u, c.headers(), nil, &out); err != nil {
+		return nil, err
+	}
+	if out.Response.Result != "success" {
+		return nil, fmt.Errorf("tautulli get_server_info failed: result=%s msg=%v", out.Response.Result, out.Response.Message)
+	}
+	return &out.Response.Data, nil
+}
+
+func (c *Client) apiURL(cmd string) (string, error) {
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(u.Path, "/api/") {
+		u.Path = strings.TrimRight(u.Path, "/") + "/api/v2"
+	}
+	q := u.Query()
+	q.Set("apikey", c.APIKey)
+	q.Set("cmd", cmd)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
 func (c *Client) ImageProxyURL(imgPath string, width int) string {
 	imgPath = strings.TrimSpace(imgPath)
 	if imgPath == "" {
@@ -0,0 +1,302 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
+	"github.com/KevinHaeusler/go-haruki/bot/ui"
+	"github.com/KevinHaeusler/go-haruki/bot/util"
+)
+
+const (
+	// Discord sends one autocomplete interaction per keystroke and drops the
+	// answer after 3 seconds.
+	autocompleteDebounce  = 300 * time.Millisecond
+	autocompleteTimeout   = 2 * time.Second
+	autocompleteMinChars  = 2
+	autocompleteMaxChoice = 25
+
+	searchCacheTTL  = 2 * time.Minute
+	searchCacheSize = 500
+	libraryCacheTTL = 5 * time.Minute
+	// libraryLoadTimeout bounds a Radarr or Sonarr library fetch, which
+	// outlives the autocomplete that started it.
+	libraryLoadTimeout = time.Minute
+)
+
+// Suggestions submit "<media type>:<id>" instead of the title, so picking one
+// skips the search results menu. Typed text that doesn't look like this is
+// searched as before.
+var mediaPickRe = regexp.MustCompile(`^(tv|movie):(\d+)$`)
+
+func mediaPickValue(mediaType string, id int) string {
+	return mediaType + ":" + strconv.Itoa(id)
+}
+
+// parseMediaPick reports whether v is a suggestion picked from autocomplete.
+func parseMediaPick(v string) (mediaType string, id int, ok bool) {
+	m := mediaPickRe.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return "", 0, false
+	}
+	id, err := strconv.Atoi(m[2])
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+	return m[1], id, true
+}
+
+var (
+	jellySearchCache = newTTLCache[[]jellyseerr.MediaSummary](searchCacheTTL, searchCacheSize)
+	libraryCache     = newTTLCache[[]pfmMedia](libraryCacheTTL, 2)
+	autocompleteWait = newDebouncer()
+
+	libraryLoadsMu sync.Mutex
+	libraryLoads   = make(map[string]chan struct{}) // media type -> done
+)
+
+// PlexRequestAutocomplete suggests Jellyseerr search results for the media option.
+func PlexRequestAutocomplete(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	q, mt, ok := autocompleteQuery(i)
+	if !ok || ctx.Jelly == nil {
+		return respondChoices(s, i, nil)
+	}
+
+	key := mt + "|" + strings.ToLower(q)
+	results, ok := jellySearchCache.get(key)
+	if !ok {
+		if !autocompleteWait.wait(ctx.Context(), util.InteractionUserID(i)+"|"+PlexRequestCommand.Name) {
+			// a newer keystroke will answer
+			return respondChoices(s, i, nil)
+		}
+		callCtx, cancel := context.WithTimeout(ctx.Context(), autocompleteTimeout)
+		defer cancel()
+		var err error
+		results, err = ctx.Jelly.SearchSummary(callCtx, q, mt)
+		if err != nil {
+			ctx.Log.Debug("plex-request autocomplete failed", "query", q, "err", err)
+			return respondChoices(s, i, nil)
+		}
+		jellySearchCache.set(key, results)
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, autocompleteMaxChoice)
+	for _, r := range results {
+		if r.MediaType != "tv" && r.MediaType != "movie" {
+			continue // people
+		}
+		name := r.Title
+		if r.Year != "" {
+			name = fmt.Sprintf("%s (%s)", r.Title, r.Year)
+		}
+		kind := "Movie"
+		if r.MediaType == "tv" {
+			kind = "TV"
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  ui.Truncate(name, 100-len(kind)-3) + " · " + kind,
+			Value: mediaPickValue(r.MediaType, r.ID),
+		})
+		if len(choices) == autocompleteMaxChoice {
+			break
+		}
+	}
+	return respondChoices(s, i, choices)
+}
+
+// PlexFixMissingAutocomplete suggests titles from the Radarr or Sonarr library.
+func PlexFixMissingAutocomplete(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	q, mt, ok := autocompleteQuery(i)
+	if !ok || (mt == "movie" && ctx.Radarr == nil) || (mt == "tv" && ctx.Sonarr == nil) || mt == "" {
+		return respondChoices(s, i, nil)
+	}
+
+	library, ok := libraryCache.get(mt)
+	if !ok {
+		// only a cache miss waits for typing to settle
+		if !autocompleteWait.wait(ctx.Context(), util.InteractionUserID(i)+"|"+PlexFixMissingCommand.Name) {
+			return respondChoices(s, i, nil)
+		}
+		wait := time.NewTimer(autocompleteTimeout)
+		defer wait.Stop()
+		select {
+		case <-loadLibrary(ctx, mt):
+		case <-wait.C:
+			// a later keystroke finds it in the cache
+			ctx.Log.Debug("plex-fix-missing library still loading", "media_type", mt)
+			return respondChoices(s, i, nil)
+		}
+		if library, ok = libraryCache.get(mt); !ok {
+			return respondChoices(s, i, nil)
+		}
+	}
+
+	qL := strings.ToLower(q)
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, autocompleteMaxChoice)
+	for _, m := range library {
+		if !strings.Contains(strings.ToLower(m.Title), qL) {
+			continue
+		}
+		name := m.Title
+		if m.Year > 0 {
+			name = fmt.Sprintf("%s (%d)", m.Title, m.Year)
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  ui.Truncate(name, 100),
+			Value: mediaPickValue(mt, m.ID),
+		})
+		if len(choices) == autocompleteMaxChoice {
+			break
+		}
+	}
+	return respondChoices(s, i, choices)
+}
+
+// loadLibrary fetches the Radarr or Sonarr library into libraryCache, unless
+// a fetch is already running. It runs detached with its own timeout, so a
+// large library still lands in the cache after the autocomplete that asked
+// for it has been answered. The returned channel is closed when it is done.
+func loadLibrary(ctx *appctx.Context, mediaType string) <-chan struct{} {
+	libraryLoadsMu.Lock()
+	defer libraryLoadsMu.Unlock()
+	if done, ok := libraryLoads[mediaType]; ok {
+		return done
+	}
+	done := make(chan struct{})
+	libraryLoads[mediaType] = done
+
+	go func() {
+		defer func() {
+			libraryLoadsMu.Lock()
+			delete(libraryLoads, mediaType)
+			libraryLoadsMu.Unlock()
+			close(done)
+		}()
+		callCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx.Context()), libraryLoadTimeout)
+		defer cancel()
+		library, err := pfmListLibrary(callCtx, ctx, mediaType)
+		if err != nil {
+			ctx.Log.Warn("plex-fix-missing library load failed", "media_type", mediaType, "err", err)
+			return
+		}
+		libraryCache.set(mediaType, library)
+	}()
+	return done
+}
+
+// autocompleteQuery returns the text typed into the focused media option and
+// the media-type option, if already filled in.
+func autocompleteQuery(i *discordgo.InteractionCreate) (q, mediaType string, ok bool) {
+	for _, o := range i.ApplicationCommandData().Options {
+		switch {
+		case o.Name == "media-type":
+			mediaType = strings.ToLower(strings.TrimSpace(o.StringValue()))
+		case o.Name == "media" && o.Focused:
+			q = strings.TrimSpace(o.StringValue())
+			ok = true
+		}
+	}
+	if mediaType != "tv" && mediaType != "movie" {
+		mediaType = ""
+	}
+	return q, mediaType, ok && len([]rune(q)) >= autocompleteMinChars
+}
+
+func respondChoices(s *discordgo.Session, i *discordgo.InteractionCreate, choices []*discordgo.ApplicationCommandOptionChoice) error {
+	if choices == nil {
+		choices = []*discordgo.ApplicationCommandOptionChoice{}
+	}
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+}
+
+// ttlCache is a small map whose entries expire after ttl. When full, expired
+// entries are dropped first, then the oldest one.
+type ttlCache[V any] struct {
+	ttl time.Duration
+	max int
+
+	mu      sync.Mutex
+	entries map[string]ttlEntry[V]
+}
+
+type ttlEntry[V any] struct {
+	value V
+	added time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration, max int) *ttlCache[V] {
+	return &ttlCache[V]{ttl: ttl, max: max, entries: make(map[string]ttlEntry[V])}
+}
+
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Since(e.added) > c.ttl {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (c *ttlCache[V]) set(key string, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.max {
+		oldest := ""
+		for k, e := range c.entries {
+			if time.Since(e.added) > c.ttl {
+				delete(c.entries, k)
+				continue
+			}
+			if oldest == "" || e.added.Before(c.entries[oldest].added) {
+				oldest = k
+			}
+		}
+		if len(c.entries) >= c.max {
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[key] = ttlEntry[V]{value: v, added: time.Now()}
+}
+
+// debouncer lets only the last of a burst of calls with the same key through.
+type debouncer struct {
+	mu  sync.Mutex
+	seq map[string]uint64
+}
+
+func newDebouncer() *debouncer {
+	return &debouncer{seq: make(map[string]uint64)}
+}
+
+// wait blocks for autocompleteDebounce and reports whether no newer call
+// with the same key arrived in the meantime.
+func (d *debouncer) wait(ctx context.Context, key string) bool {
+	d.mu.Lock()
+	d.seq[key]++
+	mine := d.seq[key]
+	d.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(autocompleteDebounce):
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.seq[key] == mine
+}
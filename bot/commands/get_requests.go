@@ -1,18 +1,20 @@
 package commands
 
 import (
-	"context"
+	"errors"
 	"fmt"
-	"time"
+	"strconv"
 
 	"github.com/KevinHaeusler/go-haruki/bot/appctx"
 	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
-	"github.com/KevinHaeusler/go-haruki/bot/session"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
 	"github.com/KevinHaeusler/go-haruki/bot/ui"
 	"github.com/KevinHaeusler/go-haruki/bot/util"
 	"github.com/bwmarrin/discordgo"
 )
 
+const getRequestsPageSize = 20
+
 var (
 	GetRequestsCommand = &discordgo.ApplicationCommand{
 		Name:        "get-requests",
@@ -33,27 +35,50 @@ var (
 		},
 	}
 
-	GetRequestsPrevID  = "get_requests_prev"
-	GetRequestsNextID  = "get_requests_next"
-	GetRequestsAbortID = "get_requests_abort"
-
-	getRequestsSessions = session.NewStore[getRequestsSession](180 * time.Second)
+	// The list is stateless: every button carries what it needs to rebuild
+	// its page, so paging keeps working after a restart.
+	//
+	//	req:page:<owner>:<target>:<finished 0|1>:<page>
+	//	req:close:<owner>
+	GetRequestsPageID  = "req:page"
+	GetRequestsCloseID = "req:close"
 )
 
-type getRequestsSession struct {
-	DiscordUser     *discordgo.User
-	JellyUserID     int
-	Page            int
-	Take            int
+// getRequestsView is the state of one request list message.
+type getRequestsView struct {
+	OwnerID         string // who ran the command
+	TargetID        string // whose requests are listed
 	IncludeFinished bool
-	AllResults      []jellyseerr.UserRequest
-	MessageID       string
-	ChannelID       string
+	Page            int
+}
+
+func parseGetRequestsView(args []string) (getRequestsView, bool) {
+	if len(args) != 4 {
+		return getRequestsView{}, false
+	}
+	page, err := strconv.Atoi(args[3])
+	if err != nil || page < 1 {
+		return getRequestsView{}, false
+	}
+	return getRequestsView{
+		OwnerID:         args[0],
+		TargetID:        args[1],
+		IncludeFinished: args[2] == "1",
+		Page:            page,
+	}, true
+}
+
+func (v getRequestsView) pageID(page int) string {
+	finished := "0"
+	if v.IncludeFinished {
+		finished = "1"
+	}
+	return componentID(GetRequestsPageID, v.OwnerID, v.TargetID, finished, strconv.Itoa(page))
 }
 
 func GetRequestsHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	if ctx.Jelly == nil {
-		return util.RespondEphemeral(s, i, "Jellyseerr client not configured.")
+		return notRun(util.RespondEphemeral(s, i, "Jellyseerr client not configured."))
 	}
 
 	// Determine who ran the command
@@ -75,152 +100,151 @@ func GetRequestsHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.
 		}
 	}
 
-	// Resolve Discord User to Jellyseerr User ID
-	jellyID, err := ctx.Jelly.DiscordUserToJellyseerrUserID(context.Background(), targetUser.ID)
-	if err != nil {
-		return util.RespondEphemeral(s, i, fmt.Sprintf("Error resolving user: %v", err))
-	}
-	if jellyID == 0 {
-		return util.RespondEphemeral(s, i, fmt.Sprintf("Discord user %s is not linked to Jellyseerr.", targetUser.Username))
+	view := getRequestsView{
+		OwnerID:         invoker.ID,
+		TargetID:        targetUser.ID,
+		IncludeFinished: includeFinished,
+		Page:            1,
 	}
 
-	take := 20
-	results, err := ctx.Jelly.GetUserRequests(context.Background(), jellyID, includeFinished)
+	results, msg, err := fetchGetRequests(ctx, view, targetUser)
 	if err != nil {
-		return util.RespondEphemeral(s, i, fmt.Sprintf("Error fetching requests: %v", err))
-	}
-
-	sess := getRequestsSession{
-		DiscordUser:     targetUser,
-		JellyUserID:     jellyID,
-		Page:            1,
-		Take:            take,
-		IncludeFinished: includeFinished,
-		AllResults:      results,
+		if rerr := util.RespondEphemeral(s, i, msg); rerr != nil {
+			return rerr
+		}
+		if errors.Is(err, errNotLinked) {
+			return ErrNotRun
+		}
+		return reported(err)
 	}
 
-	embed, comps := buildGetRequestsPage(&sess)
+	embed, comps := buildGetRequestsPage(view, targetUser, results)
 
-	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds:     []*discordgo.MessageEmbed{embed},
 			Components: comps,
 		},
 	})
-	if err != nil {
-		return err
-	}
-
-	msg, err := s.InteractionResponse(i.Interaction)
-	if err == nil {
-		sess.MessageID = msg.ID
-		sess.ChannelID = msg.ChannelID
-		getRequestsSessions.Set(invoker.ID, sess)
-		go getRequestsExpireLoop(s, invoker.ID, sess.ChannelID, sess.MessageID)
-	}
-
-	return nil
 }
 
-func GetRequestsPrevHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	invoker := i.Member.User
-	if invoker == nil {
-		invoker = i.User
-	}
-	sess := getRequestsSessions.Get(invoker.ID)
-	if sess == nil || sess.Page <= 1 {
+// GetRequestsPageHandler handles Previous and Next; the target page is part
+// of the CustomID.
+func GetRequestsPageHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	view, ok := parseGetRequestsView(componentArgs(i.MessageComponentData().CustomID, GetRequestsPageID))
+	if !ok || ctx.Jelly == nil {
 		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseDeferredMessageUpdate,
 		})
 	}
+	if !checkOwner(ctx, s, i, view.OwnerID, permissions.UseAnySession) {
+		return nil
+	}
 
-	sess.Page--
-	return updateGetRequestsPage(ctx, s, i, sess)
-}
+	// Refetching can take a moment, so acknowledge first.
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		return err
+	}
 
-func GetRequestsNextHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	invoker := i.Member.User
-	if invoker == nil {
-		invoker = i.User
+	target, err := s.User(view.TargetID)
+	if err != nil {
+		target = &discordgo.User{ID: view.TargetID, Username: view.TargetID}
 	}
-	sess := getRequestsSessions.Get(invoker.ID)
-	totalPages := (len(sess.AllResults) + sess.Take - 1) / sess.Take
-	if sess == nil || sess.Page >= totalPages {
-		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseDeferredMessageUpdate,
+
+	results, msg, err := fetchGetRequests(ctx, view, target)
+	if err != nil {
+		_, _ = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: msg,
+			Flags:   discordgo.MessageFlagsEphemeral,
 		})
+		return nil
 	}
 
-	sess.Page++
-	return updateGetRequestsPage(ctx, s, i, sess)
+	embed, comps := buildGetRequestsPage(view, target, results)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &comps,
+	})
+	return err
 }
 
-func GetRequestsAbortHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	invoker := i.Member.User
-	if invoker == nil {
-		invoker = i.User
+func GetRequestsCloseHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	args := componentArgs(i.MessageComponentData().CustomID, GetRequestsCloseID)
+	if len(args) != 1 {
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredMessageUpdate,
+		})
+	}
+	if !checkOwner(ctx, s, i, args[0], permissions.AbortAnySession) {
+		return nil
 	}
-	getRequestsSessions.Clear(invoker.ID)
 	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseUpdateMessage,
 		Data: &discordgo.InteractionResponseData{
 			Content:    "Request list aborted.",
-			Embeds:     nil,
-			Components: nil,
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
 		},
 	})
 }
 
-func updateGetRequestsPage(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, sess *getRequestsSession) error {
-	invoker := i.Member.User
-	if invoker == nil {
-		invoker = i.User
-	}
-	getRequestsSessions.Touch(invoker.ID)
-
-	embed, comps := buildGetRequestsPage(sess)
-	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseUpdateMessage,
-		Data: &discordgo.InteractionResponseData{
-			Embeds:     []*discordgo.MessageEmbed{embed},
-			Components: comps,
-		},
-	})
-}
+// fetchGetRequests loads every request of the view's target user. On error,
+// msg is the text to show the user.
+// errNotLinked is returned by fetchGetRequests for a user with no Jellyseerr
+// account linked.
+var errNotLinked = errors.New("not linked to Jellyseerr")
 
-func buildGetRequestsPage(sess *getRequestsSession) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
-	start := (sess.Page - 1) * sess.Take
-	end := start + sess.Take
-	if end > len(sess.AllResults) {
-		end = len(sess.AllResults)
+func fetchGetRequests(ctx *appctx.Context, view getRequestsView, target *discordgo.User) (results []jellyseerr.UserRequest, msg string, err error) {
+	jellyID, err := ctx.Jelly.DiscordUserToJellyseerrUserID(ctx.Context(), view.TargetID)
+	if err != nil {
+		return nil, fmt.Sprintf("Error resolving user: %v", err), err
+	}
+	if jellyID == 0 {
+		return nil, fmt.Sprintf("Discord user %s is not linked to Jellyseerr.", target.Username), fmt.Errorf("user %s: %w", view.TargetID, errNotLinked)
 	}
 
-	var pageResults []jellyseerr.UserRequest
-	if start < len(sess.AllResults) {
-		pageResults = sess.AllResults[start:end]
+	results, err = ctx.Jelly.GetUserRequests(ctx.Context(), jellyID, view.IncludeFinished)
+	if err != nil {
+		return nil, fmt.Sprintf("Error fetching requests: %v", err), err
 	}
+	return results, "", nil
+}
 
-	totalPages := (len(sess.AllResults) + sess.Take - 1) / sess.Take
+func buildGetRequestsPage(view getRequestsView, target *discordgo.User, results []jellyseerr.UserRequest) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	totalPages := (len(results) + getRequestsPageSize - 1) / getRequestsPageSize
 	if totalPages == 0 {
 		totalPages = 1
 	}
+	// the list may have shrunk since the button was rendered
+	page := min(view.Page, totalPages)
+
+	start := (page - 1) * getRequestsPageSize
+	end := min(start+getRequestsPageSize, len(results))
+
+	var pageResults []jellyseerr.UserRequest
+	if start < len(results) {
+		pageResults = results[start:end]
+	}
 
-	embed := ui.JellyRequestListEmbed(sess.DiscordUser, pageResults, sess.Page, totalPages, len(sess.AllResults))
+	embed := ui.JellyRequestListEmbed(target, pageResults, page, totalPages, len(results))
 
+	// Prev and Next point at different pages, so their CustomIDs never clash.
 	prevBtn := discordgo.Button{
 		Label:    "Previous",
 		Style:    discordgo.SecondaryButton,
-		CustomID: GetRequestsPrevID,
-		Disabled: sess.Page <= 1,
+		CustomID: view.pageID(page - 1),
+		Disabled: page <= 1,
 	}
 	nextBtn := discordgo.Button{
 		Label:    "Next",
 		Style:    discordgo.SecondaryButton,
-		CustomID: GetRequestsNextID,
-		Disabled: sess.Page >= totalPages,
+		CustomID: view.pageID(page + 1),
+		Disabled: page >= totalPages,
 	}
-	abortBtn := ui.AbortButton(GetRequestsAbortID)
+	abortBtn := ui.AbortButton(componentID(GetRequestsCloseID, view.OwnerID))
 
 	comps := []discordgo.MessageComponent{
 		discordgo.ActionsRow{
@@ -230,22 +254,3 @@ func buildGetRequestsPage(sess *getRequestsSession) (*discordgo.MessageEmbed, []
 
 	return embed, comps
 }
-
-func getRequestsExpireLoop(s *discordgo.Session, userID string, channelID string, messageID string) {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		sess, expired := getRequestsSessions.GetWithExpiration(userID)
-		if sess == nil {
-			if expired {
-				_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-					Channel:    channelID,
-					ID:         messageID,
-					Components: &[]discordgo.MessageComponent{},
-				})
-			}
-			return
-		}
-	}
-}
@@ -19,7 +19,9 @@ func HelpHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.Interac
 		Fields: []*discordgo.MessageEmbedField{
 			{Name: "/help", Value: "Show this help message"},
 			{Name: "/ping", Value: "Check if the bot is online"},
+			{Name: "/status", Value: "Check whether Jellyseerr, Sonarr, Radarr and Tautulli are up"},
 			{Name: "/plex-request <mediaType> <media>", Value: "Search Jellyseerr for a movie or TV show"},
+			{Name: "/plex-discover <category> [filter]", Value: "Browse trending, popular and upcoming titles and request them"},
 			{Name: "/jelly-link", Value: "Link your Discord account to a Jellyseerr user"},
 		},
 	}
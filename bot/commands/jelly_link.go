@@ -10,6 +10,7 @@ import (
 
 	"github.com/KevinHaeusler/go-haruki/bot/appctx"
 	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
 	"github.com/KevinHaeusler/go-haruki/bot/session"
 	"github.com/KevinHaeusler/go-haruki/bot/ui"
 	"github.com/KevinHaeusler/go-haruki/bot/util"
@@ -47,6 +48,7 @@ type jellyLinkCandidate struct {
 }
 
 type jellyLinkSession struct {
+	ID              string
 	OwnerDiscordID  string // who ran the command
 	TargetDiscordID string // discord id that will be assigned
 	IsAdmin         bool
@@ -60,12 +62,14 @@ type jellyLinkSession struct {
 }
 
 var (
-	jellyLinkStore = session.NewStore[jellyLinkSession](jellyLinkTTL)
+	jellyLinkStore = session.New[jellyLinkSession](nil, JellyLinkCommand.Name, jellyLinkTTL)
 )
 
+func (j jellyLinkSession) owner() string { return j.OwnerDiscordID }
+
 func JellyLinkHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	if ctx.Jelly == nil {
-		return util.RespondEphemeral(s, i, "Jellyseerr is not configured.")
+		return notRun(util.RespondEphemeral(s, i, "Jellyseerr is not configured."))
 	}
 
 	ownerID := i.Member.User.ID
@@ -74,7 +78,11 @@ func JellyLinkHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.In
 		targetID = u.ID
 	}
 
-	isAdmin := util.UserIsAdmin(i)
+	// Linking someone else (and seeing already linked users) is an admin action.
+	isAdmin := ctx.Perms.Can(i, permissions.JellyLinkOthers)
+	if targetID != ownerID && !isAdmin {
+		return notRun(util.RespondEphemeral(s, i, fmt.Sprintf(permissions.DeniedMessage, "link other users")))
+	}
 
 	// Defer (ephemeral would be ideal, but component updates for ephemerals can be awkward depending on your flow.
 	// We'll keep it normal response here; change to ephemeral if you prefer.)
@@ -85,7 +93,7 @@ func JellyLinkHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.In
 	}
 
 	// Build candidate list (paged fetch + detail check for settings.discordId)
-	callCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 30*time.Second)
 	defer cancel()
 
 	candidates, err := fetchJellyLinkCandidates(callCtx, ctx.Jelly, isAdmin)
@@ -93,7 +101,7 @@ func JellyLinkHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.In
 		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: util.PtrString("Failed to load Jellyseerr users: " + err.Error()),
 		})
-		return nil
+		return reported(err)
 	}
 
 	if len(candidates) == 0 {
@@ -104,10 +112,11 @@ func JellyLinkHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.In
 		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: util.PtrString(msg),
 		})
-		return nil
+		return ErrNotRun
 	}
 
 	sess := &jellyLinkSession{
+		ID:              session.NewID(),
 		OwnerDiscordID:  ownerID,
 		TargetDiscordID: targetID,
 		IsAdmin:         isAdmin,
@@ -121,14 +130,13 @@ func JellyLinkHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.In
 		Components: &comps,
 	})
 	if err != nil {
-		return nil
+		return err
 	}
 
 	sess.ChannelID = msg.ChannelID
 	sess.MessageID = msg.ID
 
-	jellyLinkStore.Set(ownerID, *sess)
-	go jellyLinkExpireLoop(s, ownerID, msg.ChannelID, msg.ID)
+	jellyLinkStore.Set(sess.ID, *sess)
 
 	return nil
 }
@@ -146,23 +154,21 @@ func fetchJellyLinkCandidates(ctx context.Context, c *jellyseerr.Client, isAdmin
 		}
 
 		for _, u := range users {
-			detail, err := c.GetUserDetail(ctx, u.ID)
+			discordID, err := c.JellyseerrUserToDiscordID(ctx, u.ID)
 			if err != nil {
 				// If one user detail fails, skip it (don’t break the whole command)
 				continue
 			}
 
-			discordID := detail.Settings.DiscordID
-
 			// Non-admins see only unassigned
 			if !isAdmin && discordID != "" {
 				continue
 			}
 
 			all = append(all, jellyLinkCandidate{
-				ID:          detail.ID,
-				DisplayName: detail.DisplayName,
-				Email:       detail.Email,
+				ID:          u.ID,
+				DisplayName: u.DisplayName,
+				Email:       u.Email,
 				DiscordID:   discordID,
 			})
 		}
@@ -176,16 +182,13 @@ func fetchJellyLinkCandidates(ctx context.Context, c *jellyseerr.Client, isAdmin
 }
 
 func JellyLinkSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredMessageUpdate,
-	})
-
-	ownerID := i.Member.User.ID
-	sess := jellyLinkStore.Get(ownerID)
+	sess := openSession(ctx, s, i, jellyLinkStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	jellyLinkStore.Touch(ownerID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
 
 	vals := i.MessageComponentData().Values
 	if len(vals) == 0 {
@@ -197,10 +200,10 @@ func JellyLinkSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discor
 	}
 	jellyUserName, err := jellyseerr.GetUserName(ctx.Jelly, jellyUserID)
 	if err != nil {
-		return nil
+		return fmt.Errorf("look up jellyseerr user %d: %w", jellyUserID, err)
 	}
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 20*time.Second)
 	defer cancel()
 
 	// Update Jellyseerr user settings.discordId and enable discord notifications
@@ -215,7 +218,7 @@ func JellyLinkSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discor
 			Description: "Jellyseerr returned an error: " + err.Error(),
 			Color:       0xff0000,
 		}
-		jellyLinkStore.Clear(ownerID)
+		jellyLinkStore.Clear(sess.ID)
 		return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, []discordgo.MessageComponent{})
 	}
 
@@ -228,72 +231,56 @@ func JellyLinkSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discor
 		Color: 0x00cc66,
 	}
 
-	jellyLinkStore.Clear(ownerID)
+	jellyLinkStore.Clear(sess.ID)
 	return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, []discordgo.MessageComponent{})
 }
 
 func JellyLinkPrevHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = ctx
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-
-	ownerID := i.Member.User.ID
-	sess := jellyLinkStore.Get(ownerID)
+	sess := openSession(ctx, s, i, jellyLinkStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	jellyLinkStore.Touch(ownerID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
 
 	if sess.Page > 0 {
 		sess.Page--
 	}
-	jellyLinkStore.Set(ownerID, *sess)
+	jellyLinkStore.Set(sess.ID, *sess)
 
 	embed, comps := buildJellyLinkPage(sess)
 	return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, comps)
 }
 
 func JellyLinkNextHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = ctx
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-
-	ownerID := i.Member.User.ID
-	sess := jellyLinkStore.Get(ownerID)
+	sess := openSession(ctx, s, i, jellyLinkStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	jellyLinkStore.Touch(ownerID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
 
 	maxPage := (len(sess.Candidates) - 1) / jellyLinkPageSize
 	if sess.Page < maxPage {
 		sess.Page++
 	}
-	jellyLinkStore.Set(ownerID, *sess)
+	jellyLinkStore.Set(sess.ID, *sess)
 
 	embed, comps := buildJellyLinkPage(sess)
 	return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, comps)
 }
 
 func JellyLinkAbortHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = ctx
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-
-	ownerID := i.Member.User.ID
-	sess := jellyLinkStore.Get(ownerID)
+	sess := openSession(ctx, s, i, jellyLinkStore, permissions.AbortAnySession)
 	if sess == nil {
 		return nil
 	}
-
-	// Only owner or admin can abort
-	if ownerID != sess.OwnerDiscordID && !util.UserIsAdmin(i) {
-		return nil
-	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
 
 	embed := &discordgo.MessageEmbed{
 		Title:       "Aborted",
 		Description: "Link session aborted.",
 		Color:       0x999999,
 	}
-	jellyLinkStore.Clear(ownerID)
+	jellyLinkStore.Clear(sess.ID)
 	return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, []discordgo.MessageComponent{})
 }
 
@@ -348,7 +335,7 @@ func buildJellyLinkPage(sess *jellyLinkSession) (*discordgo.MessageEmbed, []disc
 	selectRow := discordgo.ActionsRow{
 		Components: []discordgo.MessageComponent{
 			discordgo.SelectMenu{
-				CustomID:    JellyLinkSelectID,
+				CustomID:    withSession(JellyLinkSelectID, sess.ID),
 				Placeholder: "Choose a Jellyseerr user…",
 				Options:     opts,
 			},
@@ -360,9 +347,9 @@ func buildJellyLinkPage(sess *jellyLinkSession) (*discordgo.MessageEmbed, []disc
 
 	navRow := discordgo.ActionsRow{
 		Components: []discordgo.MessageComponent{
-			discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: JellyLinkPrevID, Disabled: prevDisabled},
-			discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: JellyLinkNextID, Disabled: nextDisabled},
-			discordgo.Button{Label: "Abort", Style: discordgo.DangerButton, CustomID: JellyLinkAbortID},
+			discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: withSession(JellyLinkPrevID, sess.ID), Disabled: prevDisabled},
+			discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: withSession(JellyLinkNextID, sess.ID), Disabled: nextDisabled},
+			discordgo.Button{Label: "Abort", Style: discordgo.DangerButton, CustomID: withSession(JellyLinkAbortID, sess.ID)},
 		},
 	}
 
@@ -371,25 +358,6 @@ func buildJellyLinkPage(sess *jellyLinkSession) (*discordgo.MessageEmbed, []disc
 
 // ---- session storage / expiry ----
 
-func jellyLinkExpireLoop(s *discordgo.Session, userID, channelID, messageID string) {
-	for {
-		time.Sleep(10 * time.Second)
-		_, expired := jellyLinkStore.GetWithExpiration(userID)
-		if expired {
-			embed := &discordgo.MessageEmbed{
-				Title:       "Aborted",
-				Description: "Session timed out after 5 minutes of inactivity.",
-				Color:       0xff0000,
-			}
-			_ = editSessionMessageSimple(s, channelID, messageID, embed, []discordgo.MessageComponent{})
-			return
-		}
-		if jellyLinkStore.Get(userID) == nil {
-			return
-		}
-	}
-}
-
 func editSessionMessageSimple(s *discordgo.Session, channelID, messageID string, embed *discordgo.MessageEmbed, comps []discordgo.MessageComponent) error {
 	embeds := []*discordgo.MessageEmbed{embed}
 	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// showModal answers an interaction by opening a modal, one text input per
+// row. The submitted modal is routed by customID through ModalHandlers.
+func showModal(s *discordgo.Session, i *discordgo.InteractionCreate, customID, title string, inputs ...discordgo.TextInput) error {
+	rows := make([]discordgo.MessageComponent, 0, len(inputs))
+	for _, in := range inputs {
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{in}})
+	}
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   customID,
+			Title:      title,
+			Components: rows,
+		},
+	})
+}
+
+// modalValues returns the submitted text inputs by their CustomID, trimmed.
+func modalValues(i *discordgo.InteractionCreate) map[string]string {
+	out := make(map[string]string)
+	for _, c := range i.ModalSubmitData().Components {
+		var inner []discordgo.MessageComponent
+		switch row := c.(type) {
+		case *discordgo.ActionsRow:
+			inner = row.Components
+		case discordgo.ActionsRow:
+			inner = row.Components
+		}
+		for _, ic := range inner {
+			switch in := ic.(type) {
+			case *discordgo.TextInput:
+				out[in.CustomID] = strings.TrimSpace(in.Value)
+			case discordgo.TextInput:
+				out[in.CustomID] = strings.TrimSpace(in.Value)
+			}
+		}
+	}
+	return out
+}
+
+// interactionCustomID returns the CustomID of a clicked component or a
+// submitted modal.
+func interactionCustomID(i *discordgo.InteractionCreate) string {
+	if i.Type == discordgo.InteractionModalSubmit {
+		return i.ModalSubmitData().CustomID
+	}
+	return i.MessageComponentData().CustomID
+}
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -18,9 +17,9 @@ var PlexActivityCommand = &discordgo.ApplicationCommand{
 }
 
 func PlexActivityHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	log.Printf("[CMD] /plex-activity invoked by %s (%s) guild=%s channel=%s", i.Member.User.Username, i.Member.User.ID, i.GuildID, i.ChannelID)
+	ctx.Log.Info("plex-activity invoked", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID)
 	if ctx.Tautulli == nil {
-		return util.RespondEphemeral(s, i, "Tautulli is not configured.")
+		return notRun(util.RespondEphemeral(s, i, "Tautulli is not configured."))
 	}
 
 	// Defer immediately (avoid Discord 3s timeout)
@@ -30,20 +29,20 @@ func PlexActivityHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo
 		return err
 	}
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 10*time.Second)
 	defer cancel()
 
 	resp, err := ctx.Tautulli.GetActivity(callCtx)
 	if err != nil {
-		log.Printf("[CMD] /plex-activity error for %s (%s): %v", i.Member.User.Username, i.Member.User.ID, err)
+		ctx.Log.Error("plex-activity fetch failed", "err", err)
 		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: util.PtrString("❌ Failed to fetch Plex activity: " + err.Error()),
 		})
-		return nil
+		return reported(err)
 	}
 
 	sessions := resp.Response.Data.Sessions
-	log.Printf("[CMD] /plex-activity sessions=%d", len(sessions))
+	ctx.Log.Info("plex-activity sessions", "count", len(sessions))
 	if len(sessions) == 0 {
 		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: util.PtrString("No active Plex sessions right now."),
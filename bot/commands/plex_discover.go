@@ -0,0 +1,482 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
+	"github.com/KevinHaeusler/go-haruki/bot/session"
+	"github.com/KevinHaeusler/go-haruki/bot/ui"
+	"github.com/KevinHaeusler/go-haruki/bot/util"
+)
+
+const (
+	PlexDiscoverPrevID  = "plex_discover_prev"
+	PlexDiscoverNextID  = "plex_discover_next"
+	PlexDiscoverAbortID = "plex_discover_abort"
+	// PlexDiscoverRequestID hands the shown title to the /plex-request flow,
+	// so it needs permission for /plex-request, not /plex-discover.
+	PlexDiscoverRequestID = "plex_discover_request"
+
+	plexDiscoverTTL = 5 * time.Minute
+	genreCacheTTL   = time.Hour
+)
+
+// discoverCategory is a choice of the category option.
+type discoverCategory struct {
+	Label  string // heading of the carousel
+	List   string // jellyseerr.Discover* list
+	Filter string // "genre", "studio" or "network" if the list needs one
+}
+
+var discoverCategories = map[string]discoverCategory{
+	"trending":        {Label: "🔥 Trending", List: jellyseerr.DiscoverTrending},
+	"popular-movies":  {Label: "🎬 Popular Movies", List: jellyseerr.DiscoverPopularMovies},
+	"popular-tv":      {Label: "📺 Popular TV", List: jellyseerr.DiscoverPopularTV},
+	"upcoming-movies": {Label: "🗓️ Upcoming Movies", List: jellyseerr.DiscoverUpcomingMovies},
+	"upcoming-tv":     {Label: "🗓️ Upcoming TV", List: jellyseerr.DiscoverUpcomingTV},
+	"movie-genre":     {Label: "🎬 Movies", List: jellyseerr.DiscoverMovieGenre, Filter: "genre"},
+	"tv-genre":        {Label: "📺 TV", List: jellyseerr.DiscoverTVGenre, Filter: "genre"},
+	"studio":          {Label: "🎬 Studio", List: jellyseerr.DiscoverStudio, Filter: "studio"},
+	"network":         {Label: "📺 Network", List: jellyseerr.DiscoverNetwork, Filter: "network"},
+}
+
+// discoverNetworks are the networks suggested for the network category, as
+// Jellyseerr has no way to search them. Any TMDB network ID can be typed in.
+var discoverNetworks = []discoverFilter{
+	{ID: 213, Name: "Netflix"},
+	{ID: 2739, Name: "Disney+"},
+	{ID: 1024, Name: "Prime Video"},
+	{ID: 2552, Name: "Apple TV+"},
+	{ID: 453, Name: "Hulu"},
+	{ID: 49, Name: "HBO"},
+	{ID: 4330, Name: "Paramount+"},
+	{ID: 3353, Name: "Peacock"},
+	{ID: 174, Name: "AMC"},
+	{ID: 67, Name: "Showtime"},
+	{ID: 318, Name: "Starz"},
+	{ID: 2, Name: "ABC"},
+	{ID: 6, Name: "NBC"},
+	{ID: 16, Name: "CBS"},
+	{ID: 19, Name: "FOX"},
+	{ID: 71, Name: "The CW"},
+	{ID: 4, Name: "BBC One"},
+	{ID: 56, Name: "Cartoon Network"},
+	{ID: 80, Name: "Adult Swim"},
+	{ID: 13, Name: "Nickelodeon"},
+}
+
+var PlexDiscoverCommand = &discordgo.ApplicationCommand{
+	Name:        "plex-discover",
+	Description: "Browse trending, popular and upcoming media to request",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "category",
+			Description: "What to browse",
+			Required:    true,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Trending", Value: "trending"},
+				{Name: "Popular movies", Value: "popular-movies"},
+				{Name: "Popular TV", Value: "popular-tv"},
+				{Name: "Upcoming movies", Value: "upcoming-movies"},
+				{Name: "Upcoming TV", Value: "upcoming-tv"},
+				{Name: "Movies by genre", Value: "movie-genre"},
+				{Name: "TV by genre", Value: "tv-genre"},
+				{Name: "Movies by studio", Value: "studio"},
+				{Name: "TV by network", Value: "network"},
+			},
+		},
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "filter",
+			Description:  "Genre, studio or network, for the categories that need one",
+			Required:     false,
+			Autocomplete: true,
+		},
+	},
+}
+
+// ---- session state ----
+
+type discoverSession struct {
+	ID       string
+	UserID   string
+	List     string
+	FilterID int
+	Heading  string
+
+	Items      []jellyseerr.DiscoverItem // of the loaded page
+	Index      int                       // the title shown
+	Page       int
+	TotalPages int
+
+	ChannelID string
+	MessageID string
+}
+
+var (
+	discoverStore = session.New[discoverSession](nil, PlexDiscoverCommand.Name, plexDiscoverTTL)
+	genreCache    = newTTLCache[[]jellyseerr.Genre](genreCacheTTL, 2)
+)
+
+func (d discoverSession) owner() string { return d.UserID }
+
+// discoverFilter is a genre, studio or network to narrow a category to.
+type discoverFilter struct {
+	ID   int
+	Name string
+}
+
+// ---- slash handler ----
+
+func PlexDiscoverHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if ctx.Jelly == nil {
+		return notRun(util.RespondEphemeral(s, i, "Jellyseerr is not configured."))
+	}
+
+	key := util.GetOptString(i, "category")
+	cat, ok := discoverCategories[key]
+	if !ok {
+		return notRun(util.RespondEphemeral(s, i, "Unknown category."))
+	}
+	filter := strings.TrimSpace(util.GetOptString(i, "filter"))
+	ctx.Log.Info("plex-discover invoked", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID, "category", key, "filter", filter)
+	if cat.Filter != "" && filter == "" {
+		return notRun(util.RespondEphemeral(s, i, fmt.Sprintf("Pick a %s in the `filter` option.", cat.Filter)))
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		return err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
+	defer cancel()
+
+	heading := cat.Label
+	f := discoverFilter{}
+	if cat.Filter != "" {
+		var err error
+		f, err = resolveDiscoverFilter(callCtx, ctx, cat, filter)
+		if err != nil {
+			ctx.Log.Error("plex-discover filter lookup failed", "category", key, "filter", filter, "err", err)
+			return editDeferred(s, i, "Failed to look up the "+cat.Filter+": "+err.Error(), err)
+		}
+		if f.ID == 0 {
+			return editDeferred(s, i, fmt.Sprintf("No %s matches `%s`.", cat.Filter, filter), nil)
+		}
+	}
+
+	page, err := plexDiscoverLoad(callCtx, ctx, cat.List, f.ID, 1, 1)
+	if err != nil {
+		ctx.Log.Error("plex-discover load failed", "category", key, "err", err)
+		return editDeferred(s, i, "Failed to load titles: "+err.Error(), err)
+	}
+	if len(page.Results) == 0 {
+		return editDeferred(s, i, "Nothing to discover here right now.", nil)
+	}
+	name := page.Name
+	if name == "" {
+		name = f.Name
+	}
+	if name != "" {
+		heading += ": " + ui.Truncate(name, 100)
+	}
+
+	sess := discoverSession{
+		ID:         session.NewID(),
+		UserID:     i.Member.User.ID,
+		List:       cat.List,
+		FilterID:   f.ID,
+		Heading:    heading,
+		Items:      page.Results,
+		Page:       page.Page,
+		TotalPages: page.TotalPages,
+	}
+	embed, comps := plexDiscoverView(&sess)
+	msg, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &comps,
+	})
+	if err != nil {
+		return err
+	}
+
+	sess.ChannelID = msg.ChannelID
+	sess.MessageID = msg.ID
+	discoverStore.Set(sess.ID, sess)
+	return nil
+}
+
+// resolveDiscoverFilter turns the filter option into a genre, studio or
+// network: a picked suggestion or typed ID is used as is, other text picks
+// the exact or else the first match. ID is 0 if nothing matches.
+func resolveDiscoverFilter(callCtx context.Context, ctx *appctx.Context, cat discoverCategory, v string) (discoverFilter, error) {
+	if id, err := strconv.Atoi(v); err == nil && id > 0 {
+		return discoverFilter{ID: id}, nil
+	}
+	matches, err := discoverFilterChoices(callCtx, ctx, cat, v)
+	if err != nil || len(matches) == 0 {
+		return discoverFilter{}, err
+	}
+	for _, m := range matches {
+		if strings.EqualFold(m.Name, v) {
+			return m, nil
+		}
+	}
+	return matches[0], nil
+}
+
+// discoverFilterChoices returns the genres, studios or networks whose name
+// contains q, or all of them for an empty q. Studios are searched and need q.
+func discoverFilterChoices(callCtx context.Context, ctx *appctx.Context, cat discoverCategory, q string) ([]discoverFilter, error) {
+	var all []discoverFilter
+	switch cat.Filter {
+	case "genre":
+		mt := "movie"
+		if cat.List == jellyseerr.DiscoverTVGenre {
+			mt = "tv"
+		}
+		genres, ok := genreCache.get(mt)
+		if !ok {
+			var err error
+			genres, err = ctx.Jelly.ListGenres(callCtx, mt)
+			if err != nil {
+				return nil, err
+			}
+			genreCache.set(mt, genres)
+		}
+		for _, g := range genres {
+			all = append(all, discoverFilter{ID: g.ID, Name: g.Name})
+		}
+	case "network":
+		all = discoverNetworks
+	case "studio":
+		if q == "" {
+			return nil, nil
+		}
+		companies, err := ctx.Jelly.SearchCompanies(callCtx, q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]discoverFilter, 0, len(companies))
+		for _, c := range companies {
+			out = append(out, discoverFilter{ID: c.ID, Name: c.Name})
+		}
+		return out, nil
+	}
+
+	qL := strings.ToLower(q)
+	out := make([]discoverFilter, 0, len(all))
+	for _, f := range all {
+		if strings.Contains(strings.ToLower(f.Name), qL) {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// PlexDiscoverAutocomplete suggests genres, studios or networks for the
+// filter option, depending on the category.
+func PlexDiscoverAutocomplete(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	var cat discoverCategory
+	q, focused := "", false
+	for _, o := range i.ApplicationCommandData().Options {
+		switch {
+		case o.Name == "category":
+			cat = discoverCategories[o.StringValue()]
+		case o.Name == "filter" && o.Focused:
+			q = strings.TrimSpace(o.StringValue())
+			focused = true
+		}
+	}
+	if !focused || cat.Filter == "" || ctx.Jelly == nil {
+		return respondChoices(s, i, nil)
+	}
+	if cat.Filter == "studio" {
+		if len([]rune(q)) < autocompleteMinChars {
+			return respondChoices(s, i, nil)
+		}
+		if !autocompleteWait.wait(ctx.Context(), util.InteractionUserID(i)+"|"+PlexDiscoverCommand.Name) {
+			return respondChoices(s, i, nil)
+		}
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx.Context(), autocompleteTimeout)
+	defer cancel()
+	matches, err := discoverFilterChoices(callCtx, ctx, cat, q)
+	if err != nil {
+		ctx.Log.Debug("plex-discover autocomplete failed", "filter", cat.Filter, "query", q, "err", err)
+		return respondChoices(s, i, nil)
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, autocompleteMaxChoice)
+	for _, m := range matches[:min(autocompleteMaxChoice, len(matches))] {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  ui.Truncate(m.Name, 100),
+			Value: strconv.Itoa(m.ID),
+		})
+	}
+	return respondChoices(s, i, choices)
+}
+
+// plexDiscoverLoad returns the first page from page on, walking in the
+// direction of step, that has a movie or show on it. Like plexRequestSearch
+// it gives up after searchSkipPages pages or at either end.
+func plexDiscoverLoad(callCtx context.Context, ctx *appctx.Context, list string, filterID, page, step int) (jellyseerr.DiscoverPage, error) {
+	var res jellyseerr.DiscoverPage
+	for n := 0; n < searchSkipPages; n++ {
+		var err error
+		res, err = ctx.Jelly.Discover(callCtx, list, filterID, page)
+		if err != nil {
+			return jellyseerr.DiscoverPage{}, err
+		}
+		if len(res.Results) > 0 {
+			break
+		}
+		page += step
+		if page < 1 || page > res.TotalPages {
+			break
+		}
+	}
+	return res, nil
+}
+
+// plexDiscoverView is the carousel showing the current title, with Prev,
+// Next, Request and Abort. Request is disabled once a title is available.
+func plexDiscoverView(sess *discoverSession) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	item := sess.Items[sess.Index]
+	embed := ui.JellyDiscoverEmbed(sess.Heading, item, sess.Index+1, len(sess.Items), sess.Page, sess.TotalPages)
+
+	first := sess.Page <= 1 && sess.Index == 0
+	last := sess.Page >= sess.TotalPages && sess.Index == len(sess.Items)-1
+	comps := []discordgo.MessageComponent{
+		ui.ButtonsRow(
+			discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: withSession(PlexDiscoverPrevID, sess.ID), Disabled: first},
+			discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: withSession(PlexDiscoverNextID, sess.ID), Disabled: last},
+			discordgo.Button{Label: "Request", Style: discordgo.SuccessButton, CustomID: withSession(PlexDiscoverRequestID, sess.ID), Disabled: item.Status == 5},
+			ui.AbortButton(withSession(PlexDiscoverAbortID, sess.ID)),
+		),
+	}
+	return embed, comps
+}
+
+// ---- component handlers ----
+
+// PlexDiscoverPageHandler moves to the previous or next title, loading the
+// neighbouring page at either end of the current one.
+func PlexDiscoverPageHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, discoverStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	step := 1
+	if i.MessageComponentData().CustomID == withSession(PlexDiscoverPrevID, sess.ID) {
+		step = -1
+	}
+
+	if idx := sess.Index + step; idx >= 0 && idx < len(sess.Items) {
+		sess.Index = idx
+	} else {
+		next := sess.Page + step
+		if next < 1 || next > sess.TotalPages {
+			return nil
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
+		defer cancel()
+
+		page, err := plexDiscoverLoad(callCtx, ctx, sess.List, sess.FilterID, next, step)
+		if err != nil {
+			ctx.Log.Error("plex-discover load failed", "list", sess.List, "page", next, "err", err)
+			return util.FollowupEphemeral(s, i, "Failed to load titles: "+err.Error())
+		}
+		if len(page.Results) == 0 {
+			return util.FollowupEphemeral(s, i, "No more titles.")
+		}
+		sess.Items = page.Results
+		sess.Page = page.Page
+		sess.TotalPages = page.TotalPages
+		sess.Index = 0
+		if step < 0 {
+			sess.Index = len(sess.Items) - 1
+		}
+	}
+
+	discoverStore.Set(sess.ID, *sess)
+	embed, comps := plexDiscoverView(sess)
+	return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, comps)
+}
+
+// PlexDiscoverRequestHandler turns the carousel into the /plex-request detail
+// view of the shown title; the request flow takes over from there.
+func PlexDiscoverRequestHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, discoverStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if len(sess.Items) == 0 {
+		return nil
+	}
+	item := sess.Items[min(max(sess.Index, 0), len(sess.Items)-1)]
+	ctx.Log.Info("plex-discover request", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID, "media_type", item.MediaType, "media_id", item.ID)
+
+	discoverStore.Clear(sess.ID)
+	req := requestSession{
+		ID:         session.NewID(),
+		UserID:     sess.UserID,
+		MediaType:  item.MediaType,
+		Query:      item.Title,
+		Results:    []jellyseerr.MediaSummary{item.MediaSummary},
+		SelectedID: item.ID,
+		ChannelID:  sess.ChannelID,
+		MessageID:  sess.MessageID,
+	}
+	requestStore.Set(req.ID, req)
+	return plexRequestShowDetail(ctx, s, i, &req)
+}
+
+func PlexDiscoverAbortHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, discoverStore, permissions.AbortAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	ctx.Log.Info("plex-discover abort", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID)
+
+	discoverStore.Clear(sess.ID)
+	embed := &discordgo.MessageEmbed{
+		Title:       "Aborted",
+		Description: "Discover session aborted.",
+	}
+	return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, []discordgo.MessageComponent{})
+}
+
+// editDeferred replaces the deferred reply to a slash command with content,
+// for runs that end there: a failure if err is set, a refusal otherwise.
+func editDeferred(s *discordgo.Session, i *discordgo.InteractionCreate, content string, err error) error {
+	_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: util.PtrString(content),
+	})
+	if err != nil {
+		return reported(err)
+	}
+	return ErrNotRun
+}
@@ -2,15 +2,20 @@ package commands
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/clients/radarr"
+	"github.com/KevinHaeusler/go-haruki/bot/clients/sonarr"
+	"github.com/KevinHaeusler/go-haruki/bot/httpx"
+	"github.com/KevinHaeusler/go-haruki/bot/limits"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
 	"github.com/KevinHaeusler/go-haruki/bot/session"
 	"github.com/KevinHaeusler/go-haruki/bot/ui"
 	"github.com/KevinHaeusler/go-haruki/bot/util"
@@ -28,6 +33,10 @@ const (
 	PlexFixMissingPagePrev      = "plex_fix_missing_page_prev"
 	PlexFixMissingEpPageNext    = "plex_fix_missing_ep_page_next"
 	PlexFixMissingEpPagePrev    = "plex_fix_missing_ep_page_prev"
+
+	// PlexFixMissingOverrideModal asks for a reason before grabbing a rejected release.
+	PlexFixMissingOverrideModal = "plex_fix_missing_override"
+	pfmOverrideReasonInput      = "reason"
 )
 
 var PlexFixMissingCommand = &discordgo.ApplicationCommand{
@@ -45,10 +54,11 @@ var PlexFixMissingCommand = &discordgo.ApplicationCommand{
 			},
 		},
 		{
-			Type:        discordgo.ApplicationCommandOptionString,
-			Name:        "media",
-			Description: "Enter the media to search for",
-			Required:    true,
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "media",
+			Description:  "Enter the media to search for",
+			Required:     true,
+			Autocomplete: true,
 		},
 		{
 			Type:        discordgo.ApplicationCommandOptionString,
@@ -68,35 +78,55 @@ const (
 	pfmSessionTTL = 180 * time.Second
 )
 
+// pfmMedia is a movie or series search result, reduced to what the flow needs.
+type pfmMedia struct {
+	ID    int
+	Title string
+	Year  int
+}
+
+// pfmRelease is a Radarr or Sonarr release, normalized so both flows share the
+// release picker and grab logic.
+type pfmRelease struct {
+	ui.ReleaseInfo
+	GUID          string
+	IndexerID     int
+	Approved      bool
+	Rejected      bool
+	QualityWeight int
+}
+
 type pfmSession struct {
+	ID          string
 	UserID      string
 	IsMovie     bool
 	ListingMode string
 	Query       string
 
-	SearchResults []map[string]any
+	SearchResults []pfmMedia
 	Page          int
 
-	SelectedMedia map[string]any
+	SelectedMedia *pfmMedia
 
 	// TV specific
-	MissingEpisodes  []map[string]any
-	CurrentSeasonEps []map[string]any
+	MissingEpisodes  []sonarr.Episode
+	CurrentSeasonEps []sonarr.Episode
 	EpisodePage      int
 
 	// Releases
-	Releases        []map[string]any
-	SelectedRelease map[string]any
+	Releases        []pfmRelease
+	SelectedRelease *pfmRelease
 
-	ChannelID   string
-	MessageID   string
-	Interaction *discordgo.Interaction
+	ChannelID string
+	MessageID string
 }
 
 var (
-	pfmStore = session.NewStore[pfmSession](pfmSessionTTL)
+	pfmStore = session.New[pfmSession](nil, PlexFixMissingCommand.Name, pfmSessionTTL)
 )
 
+func (p pfmSession) owner() string { return p.UserID }
+
 // ---- helpers ----
 
 func pfmSelectRow(customID string, opts []discordgo.SelectMenuOption, placeholder string) discordgo.ActionsRow {
@@ -107,8 +137,141 @@ func pfmButtonsRow(btns ...discordgo.MessageComponent) discordgo.ActionsRow {
 	return ui.ButtonsRow(btns...).(discordgo.ActionsRow)
 }
 
-func pfmAbortBtn() discordgo.Button {
-	return ui.AbortButton(PlexFixMissingAbort)
+func pfmAbortBtn(sid string) discordgo.Button {
+	return ui.AbortButton(withSession(PlexFixMissingAbort, sid))
+}
+
+func pfmMediaOptions(items []pfmMedia) []discordgo.SelectMenuOption {
+	opts := make([]discordgo.SelectMenuOption, 0, len(items))
+	for _, it := range items {
+		label := it.Title
+		if it.Year > 0 {
+			label = fmt.Sprintf("%s (%d)", it.Title, it.Year)
+		}
+		opts = append(opts, discordgo.SelectMenuOption{Label: ui.Truncate(label, 100), Value: strconv.Itoa(it.ID)})
+	}
+	return opts
+}
+
+func pfmFromMovies(movies []radarr.Movie) []pfmMedia {
+	out := make([]pfmMedia, 0, len(movies))
+	for _, m := range movies {
+		out = append(out, pfmMedia{ID: m.ID, Title: m.Title, Year: m.Year})
+	}
+	return out
+}
+
+func pfmFromSeries(series []sonarr.Series) []pfmMedia {
+	out := make([]pfmMedia, 0, len(series))
+	for _, srs := range series {
+		out = append(out, pfmMedia{ID: srs.ID, Title: srs.Title, Year: srs.Year})
+	}
+	return out
+}
+
+// pfmFilterMedia keeps the items of mediaType whose title contains q, or
+// just the item picked from autocomplete. A pick of the other media type
+// matches nothing, since its ID means a different title here.
+func pfmFilterMedia(items []pfmMedia, mediaType, q string) []pfmMedia {
+	out := make([]pfmMedia, 0)
+	if pickType, id, ok := parseMediaPick(q); ok {
+		if pickType != mediaType {
+			return out
+		}
+		for _, it := range items {
+			if it.ID == id {
+				out = append(out, it)
+			}
+		}
+		return out
+	}
+	qL := strings.ToLower(q)
+	for _, it := range items {
+		if strings.Contains(strings.ToLower(it.Title), qL) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// pfmListLibrary returns every movie or series in Radarr or Sonarr.
+func pfmListLibrary(callCtx context.Context, ctx *appctx.Context, mediaType string) ([]pfmMedia, error) {
+	if mediaType == "movie" {
+		movies, err := ctx.Radarr.ListMovies(callCtx)
+		if err != nil {
+			return nil, err
+		}
+		return pfmFromMovies(movies), nil
+	}
+	series, err := ctx.Sonarr.ListSeries(callCtx)
+	if err != nil {
+		return nil, err
+	}
+	return pfmFromSeries(series), nil
+}
+
+// pfmListMissingMovies walks every page of Radarr's wanted/missing list.
+func pfmListMissingMovies(ctx context.Context, c *radarr.Client) ([]radarr.Movie, error) {
+	const pageSize = 250
+	var all []radarr.Movie
+	for page := 1; ; page++ {
+		resp, err := c.GetWantedMissing(ctx, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Records...)
+		if len(resp.Records) < pageSize || len(all) >= resp.TotalRecords {
+			return all, nil
+		}
+	}
+}
+
+func pfmReleaseFromRadarr(r radarr.Release) pfmRelease {
+	langs := make([]string, 0, len(r.Languages))
+	for _, l := range r.Languages {
+		langs = append(langs, l.Name)
+	}
+	return pfmRelease{
+		ReleaseInfo: ui.ReleaseInfo{
+			Title:      r.DisplayTitle(),
+			Quality:    r.Quality.Quality.Name,
+			Size:       r.Size,
+			Indexer:    r.Indexer,
+			Seeders:    r.Seeders,
+			Languages:  langs,
+			Score:      r.CustomFormatScore,
+			Rejections: r.Rejections,
+		},
+		GUID:          r.GUID,
+		IndexerID:     r.IndexerID,
+		Approved:      r.Approved,
+		Rejected:      r.Rejected,
+		QualityWeight: r.QualityWeight,
+	}
+}
+
+func pfmReleaseFromSonarr(r sonarr.Release) pfmRelease {
+	langs := make([]string, 0, len(r.Languages))
+	for _, l := range r.Languages {
+		langs = append(langs, l.Name)
+	}
+	return pfmRelease{
+		ReleaseInfo: ui.ReleaseInfo{
+			Title:      r.Title,
+			Quality:    r.Quality.Quality.Name,
+			Size:       r.Size,
+			Indexer:    r.Indexer,
+			Seeders:    r.Seeders,
+			Languages:  langs,
+			Score:      r.CustomFormatScore,
+			Rejections: r.Rejections,
+		},
+		GUID:          r.GUID,
+		IndexerID:     r.IndexerID,
+		Approved:      r.Approved,
+		Rejected:      r.Rejected,
+		QualityWeight: r.QualityWeight,
+	}
 }
 
 // ---- slash handler ----
@@ -122,20 +285,21 @@ func PlexFixMissingHandler(ctx *appctx.Context, s *discordgo.Session, i *discord
 		mode = "missing only"
 	}
 
-	if !util.UserHasRole(i, PlexRoleID) {
-		return util.RespondEphemeral(s, i, "You need the Plex role to use `/plex-fix-missing`.")
-	}
 	if mt != "tv" && mt != "movie" {
-		return util.RespondEphemeral(s, i, "media-type must be `tv` or `movie`.")
+		return notRun(util.RespondEphemeral(s, i, "media-type must be `tv` or `movie`."))
 	}
 	if q == "" {
-		return util.RespondEphemeral(s, i, "media cannot be empty.")
+		return notRun(util.RespondEphemeral(s, i, "media cannot be empty."))
 	}
 	if mt == "tv" && ctx.Sonarr == nil {
-		return util.RespondEphemeral(s, i, "Sonarr is not configured.")
+		return notRun(util.RespondEphemeral(s, i, "Sonarr is not configured."))
 	}
 	if mt == "movie" && ctx.Radarr == nil {
-		return util.RespondEphemeral(s, i, "Radarr is not configured.")
+		return notRun(util.RespondEphemeral(s, i, "Radarr is not configured."))
+	}
+	if pickType, _, picked := parseMediaPick(q); picked && pickType != mt {
+		// the ID belongs to the other library, where it is another title
+		return notRun(util.RespondEphemeral(s, i, fmt.Sprintf("The selected title is from the `%s` library, but media-type is `%s`. Pick a title again.", pickType, mt)))
 	}
 
 	// defer response
@@ -143,137 +307,95 @@ func PlexFixMissingHandler(ctx *appctx.Context, s *discordgo.Session, i *discord
 		return err
 	}
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
 
-	var results []map[string]any
+	var results []pfmMedia
 	if mt == "movie" {
+		var movies []radarr.Movie
+		var err error
 		if mode == "all files" {
-			var movies []map[string]any
-			if err := ctx.Radarr.Get(callCtx, "movie", &movies); err != nil {
+			movies, err = ctx.Radarr.ListMovies(callCtx)
+			if err != nil {
 				_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: util.PtrString("Fetch movies failed: " + err.Error())})
-				return nil
+				return reported(err)
 			}
-			results = movies
 		} else {
-			var resp struct {
-				Records []map[string]any `json:"records"`
-			}
-			if err := ctx.Radarr.Get(callCtx, "wanted/missing?page=1&pageSize=1000&monitored=true", &resp); err != nil {
+			movies, err = pfmListMissingMovies(callCtx, ctx.Radarr)
+			if err != nil {
 				_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: util.PtrString("Fetch missing failed: " + err.Error())})
-				return nil
+				return reported(err)
 			}
-			results = resp.Records
 		}
-		// filter by title
-		qL := strings.ToLower(q)
-		filtered := make([]map[string]any, 0)
-		for _, m := range results {
-			t, _ := m["title"].(string)
-			if strings.Contains(strings.ToLower(t), qL) {
-				filtered = append(filtered, m)
-			}
-		}
-		results = filtered
+		results = pfmFilterMedia(pfmFromMovies(movies), mt, q)
 	} else {
-		var series []map[string]any
-		if err := ctx.Sonarr.Get(callCtx, "series", &series); err != nil {
+		series, err := ctx.Sonarr.ListSeries(callCtx)
+		if err != nil {
 			_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: util.PtrString("Fetch series failed: " + err.Error())})
-			return nil
-		}
-		qL := strings.ToLower(q)
-		for _, srs := range series {
-			t, _ := srs["title"].(string)
-			if strings.Contains(strings.ToLower(t), qL) {
-				results = append(results, srs)
-			}
+			return reported(err)
 		}
+		results = pfmFilterMedia(pfmFromSeries(series), mt, q)
 	}
 
 	if len(results) == 0 {
-		msg := fmt.Sprintf("No %sresults found for `%s`.", map[bool]string{true: "", false: "missing "}[mode == "all files"], q)
+		shown := "`" + q + "`"
+		if _, _, picked := parseMediaPick(q); picked {
+			shown = "the selected title"
+		}
+		msg := fmt.Sprintf("No %sresults found for %s.", map[bool]string{true: "", false: "missing "}[mode == "all files"], shown)
 		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: util.PtrString(msg)})
-		return nil
+		return ErrNotRun
 	}
 
-	opts := make([]discordgo.SelectMenuOption, 0, min(pfmPageSize, len(results)))
-	for _, it := range results[:min(pfmPageSize, len(results))] {
-		title, _ := it["title"].(string)
-		label := title
-		if len(label) > 100 {
-			label = label[:97] + "..."
-		}
-		id := fmt.Sprintf("%v", it["id"])
-		opts = append(opts, discordgo.SelectMenuOption{Label: label, Value: id})
-	}
+	sid := session.NewID()
+	opts := pfmMediaOptions(results[:min(pfmPageSize, len(results))])
 	components := []discordgo.MessageComponent{
-		pfmSelectRow(PlexFixMissingSelectMedia, opts, "Select Media"),
-		pfmButtonsRow(pfmAbortBtn()),
+		pfmSelectRow(withSession(PlexFixMissingSelectMedia, sid), opts, "Select Media"),
+		pfmButtonsRow(pfmAbortBtn(sid)),
 	}
 
-	msg, _ := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+	msg, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 		Embeds:     &[]*discordgo.MessageEmbed{ui.PlexFixMissingMediaEmbed(1, (len(results)-1)/pfmPageSize+1)},
 		Components: &components,
 	})
+	if err != nil {
+		return err
+	}
 
-	userID := i.Member.User.ID
-	pfmStore.Set(userID, pfmSession{
-		UserID:        userID,
+	sess := pfmSession{
+		ID:            sid,
+		UserID:        i.Member.User.ID,
 		IsMovie:       mt == "movie",
 		ListingMode:   mode,
 		Query:         q,
 		SearchResults: results,
 		Page:          0,
-		Interaction:   i.Interaction,
 		ChannelID:     msg.ChannelID,
 		MessageID:     msg.ID,
-	})
-	go pfmExpire(s, userID, msg.ChannelID, msg.ID)
-	return nil
-}
+	}
+	pfmStore.Set(sid, sess)
 
-func pfmExpire(s *discordgo.Session, userID, channelID, messageID string) {
-	for {
-		time.Sleep(10 * time.Second)
-		_, expired := pfmStore.GetWithExpiration(userID)
-		if expired {
-			embed := &discordgo.MessageEmbed{
-				Title:       "Aborted",
-				Description: "Session timed out after 3 minutes of inactivity.",
-				Color:       0xff0000,
-			}
-			embeds := []*discordgo.MessageEmbed{embed}
-			_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-				ID:         messageID,
-				Channel:    channelID,
-				Embeds:     &embeds,
-				Components: &[]discordgo.MessageComponent{},
-			})
-			return
-		}
-		// Check if it was cleared manually (sess will be nil but expired will be false)
-		if pfmStore.Get(userID) == nil {
-			return
-		}
+	// Picked from autocomplete: skip the media menu.
+	if pickType, _, picked := parseMediaPick(q); picked && pickType == mt && len(results) == 1 {
+		return pfmSelectMedia(ctx, s, i, &sess, &sess.SearchResults[0])
 	}
+	return nil
 }
 
 // ---- paging media ----
 
 func PlexFixMissingMediaPagingHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	userID := i.Member.User.ID
-	sess := pfmStore.Get(userID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(userID)
-	if i.MessageComponentData().CustomID == PlexFixMissingPageNext {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+	if i.MessageComponentData().CustomID == withSession(PlexFixMissingPageNext, sess.ID) {
 		sess.Page++
 	} else if sess.Page > 0 {
 		sess.Page--
 	}
-	pfmStore.Set(userID, *sess)
+	pfmStore.Set(sess.ID, *sess)
 	return pfmSendMediaPage(s, sess)
 }
 
@@ -288,29 +410,19 @@ func pfmSendMediaPage(s *discordgo.Session, sess *pfmSession) error {
 	if end > len(sess.SearchResults) {
 		end = len(sess.SearchResults)
 	}
-	slice := sess.SearchResults[start:end]
-	opts := make([]discordgo.SelectMenuOption, 0, len(slice))
-	for _, it := range slice {
-		title, _ := it["title"].(string)
-		label := title
-		if len(label) > 100 {
-			label = label[:97] + "..."
-		}
-		id := fmt.Sprintf("%v", it["id"])
-		opts = append(opts, discordgo.SelectMenuOption{Label: label, Value: id})
-	}
+	opts := pfmMediaOptions(sess.SearchResults[start:end])
 	rows := []discordgo.MessageComponent{
-		pfmSelectRow(PlexFixMissingSelectMedia, opts, "Select Media"),
+		pfmSelectRow(withSession(PlexFixMissingSelectMedia, sess.ID), opts, "Select Media"),
 	}
 	// nav
 	btns := []discordgo.MessageComponent{}
 	if sess.Page > 0 {
-		btns = append(btns, discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: PlexFixMissingPagePrev})
+		btns = append(btns, discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: withSession(PlexFixMissingPagePrev, sess.ID)})
 	}
 	if end < len(sess.SearchResults) {
-		btns = append(btns, discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: PlexFixMissingPageNext})
+		btns = append(btns, discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: withSession(PlexFixMissingPageNext, sess.ID)})
 	}
-	btns = append(btns, pfmAbortBtn())
+	btns = append(btns, pfmAbortBtn(sess.ID))
 	rows = append(rows, pfmButtonsRow(btns...))
 
 	embeds := []*discordgo.MessageEmbed{ui.PlexFixMissingMediaEmbed(sess.Page+1, (len(sess.SearchResults)-1)/pfmPageSize+1)}
@@ -321,67 +433,78 @@ func pfmSendMediaPage(s *discordgo.Session, sess *pfmSession) error {
 // ---- media selection ----
 
 func PlexFixMissingMediaSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	sess := pfmStore.Get(i.Member.User.ID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(i.Member.User.ID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
 	choice := i.MessageComponentData().Values[0]
-	var item map[string]any
-	for _, it := range sess.SearchResults {
-		if fmt.Sprintf("%v", it["id"]) == choice {
-			item = it
+	var item *pfmMedia
+	for idx := range sess.SearchResults {
+		if strconv.Itoa(sess.SearchResults[idx].ID) == choice {
+			item = &sess.SearchResults[idx]
 			break
 		}
 	}
 	if item == nil {
 		_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("Media not found."), Components: &[]discordgo.MessageComponent{}})
-		pfmStore.Clear(sess.UserID)
+		pfmStore.Clear(sess.ID)
 		return nil
 	}
+	return pfmSelectMedia(ctx, s, i, sess, item)
+}
+
+// pfmSelectMedia moves on from the media menu: movies go straight to the
+// release search, series to the season menu.
+func pfmSelectMedia(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, sess *pfmSession, item *pfmMedia) error {
 	sess.SelectedMedia = item
-	pfmStore.Set(sess.UserID, *sess)
+	pfmStore.Set(sess.ID, *sess)
 	if sess.IsMovie {
-		return pfmShowMovieReleases(ctx, s, sess)
+		return pfmShowMovieReleases(ctx, s, i, sess)
 	}
 	// TV: fetch episodes
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
-	seriesID := fmt.Sprintf("%v", item["id"])
-	var eps []map[string]any
-	if err := ctx.Sonarr.Get(callCtx, "episode?seriesId="+seriesID, &eps); err != nil {
+	eps, err := ctx.Sonarr.ListEpisodes(callCtx, item.ID)
+	if err != nil {
 		_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("Fetch episodes failed: " + err.Error())})
 		return nil
 	}
 	if sess.ListingMode == "missing only" {
-		filtered := make([]map[string]any, 0)
+		filtered := make([]sonarr.Episode, 0)
 		for _, ep := range eps {
-			if has, _ := ep["hasFile"].(bool); !has {
+			if !ep.HasFile {
 				filtered = append(filtered, ep)
 			}
 		}
 		eps = filtered
 	}
 	sess.MissingEpisodes = eps
-	pfmStore.Set(sess.UserID, *sess)
-	// seasons
-	seen := map[int]bool{}
-	opts := []discordgo.SelectMenuOption{}
+	pfmStore.Set(sess.ID, *sess)
+	// seasons, in order, with the number of listed episodes per season
+	counts := map[int]int{}
+	seasons := []int{}
 	for _, ep := range eps {
-		se, _ := ep["seasonNumber"].(float64)
-		season := int(se)
-		if !seen[season] {
-			seen[season] = true
-			opts = append(opts, discordgo.SelectMenuOption{Label: fmt.Sprintf("Season %d", season), Value: fmt.Sprintf("%d", season)})
+		if _, ok := counts[ep.SeasonNumber]; !ok {
+			seasons = append(seasons, ep.SeasonNumber)
 		}
+		counts[ep.SeasonNumber]++
+	}
+	sort.Ints(seasons)
+	opts := []discordgo.SelectMenuOption{}
+	for _, season := range seasons[:min(pfmPageSize, len(seasons))] {
+		opts = append(opts, discordgo.SelectMenuOption{
+			Label:       fmt.Sprintf("Season %d", season),
+			Value:       strconv.Itoa(season),
+			Description: fmt.Sprintf("%d episode(s)", counts[season]),
+		})
 	}
 	if len(opts) == 0 {
 		opts = []discordgo.SelectMenuOption{{Label: "No episodes found", Value: "0"}}
 	}
 	rows := []discordgo.MessageComponent{
-		pfmSelectRow(PlexFixMissingSelectSeason, opts, "Select Season"),
-		pfmButtonsRow(pfmAbortBtn()),
+		pfmSelectRow(withSession(PlexFixMissingSelectSeason, sess.ID), opts, "Select Season"),
+		pfmButtonsRow(pfmAbortBtn(sess.ID)),
 	}
 	embeds := []*discordgo.MessageEmbed{ui.PlexFixMissingSeasonEmbed()}
 	_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Embeds: &embeds, Components: &rows})
@@ -389,26 +512,25 @@ func PlexFixMissingMediaSelectHandler(ctx *appctx.Context, s *discordgo.Session,
 }
 
 func PlexFixMissingSeasonSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	sess := pfmStore.Get(i.Member.User.ID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(i.Member.User.ID)
-	val := i.MessageComponentData().Values[0]
-	season := 0
-	fmt.Sscanf(val, "%d", &season)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+	season, err := strconv.Atoi(i.MessageComponentData().Values[0])
+	if err != nil {
+		return nil
+	}
 	// filter eps by season
-	eps := make([]map[string]any, 0)
+	eps := make([]sonarr.Episode, 0)
 	for _, ep := range sess.MissingEpisodes {
-		seF, _ := ep["seasonNumber"].(float64)
-		if int(seF) == season {
+		if ep.SeasonNumber == season {
 			eps = append(eps, ep)
 		}
 	}
 	sess.CurrentSeasonEps = eps
 	sess.EpisodePage = 0
-	pfmStore.Set(sess.UserID, *sess)
+	pfmStore.Set(sess.ID, *sess)
 	return pfmSendEpisodePage(s, sess)
 }
 
@@ -421,30 +543,22 @@ func pfmSendEpisodePage(s *discordgo.Session, sess *pfmSession) error {
 	slice := sess.CurrentSeasonEps[start:end]
 	opts := []discordgo.SelectMenuOption{}
 	for _, ep := range slice {
-		has, _ := ep["hasFile"].(bool)
 		status := "❓"
-		if has {
+		if ep.HasFile {
 			status = "✅"
 		}
-		seF, _ := ep["seasonNumber"].(float64)
-		epF, _ := ep["episodeNumber"].(float64)
-		title, _ := ep["title"].(string)
-		label := fmt.Sprintf("%s S%02dE%02d - %s", status, int(seF), int(epF), title)
-		if len(label) > 100 {
-			label = label[:97] + "..."
-		}
-		id := fmt.Sprintf("%v", ep["id"])
-		opts = append(opts, discordgo.SelectMenuOption{Label: label, Value: id})
+		label := fmt.Sprintf("%s %s", status, ep.Label())
+		opts = append(opts, discordgo.SelectMenuOption{Label: ui.Truncate(label, 100), Value: strconv.Itoa(ep.ID)})
 	}
-	rows := []discordgo.MessageComponent{pfmSelectRow(PlexFixMissingSelectEpisode, opts, "Select Episode")}
+	rows := []discordgo.MessageComponent{pfmSelectRow(withSession(PlexFixMissingSelectEpisode, sess.ID), opts, "Select Episode")}
 	btns := []discordgo.MessageComponent{}
 	if sess.EpisodePage > 0 {
-		btns = append(btns, discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: PlexFixMissingEpPagePrev})
+		btns = append(btns, discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: withSession(PlexFixMissingEpPagePrev, sess.ID)})
 	}
 	if end < len(sess.CurrentSeasonEps) {
-		btns = append(btns, discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: PlexFixMissingEpPageNext})
+		btns = append(btns, discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: withSession(PlexFixMissingEpPageNext, sess.ID)})
 	}
-	btns = append(btns, pfmAbortBtn())
+	btns = append(btns, pfmAbortBtn(sess.ID))
 	rows = append(rows, pfmButtonsRow(btns...))
 	embeds := []*discordgo.MessageEmbed{ui.PlexFixMissingEpisodeEmbed(sess.EpisodePage+1, (len(sess.CurrentSeasonEps)-1)/pfmPageSize+1)}
 	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Embeds: &embeds, Components: &rows})
@@ -452,28 +566,32 @@ func pfmSendEpisodePage(s *discordgo.Session, sess *pfmSession) error {
 }
 
 func PlexFixMissingEpisodePagingHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	sess := pfmStore.Get(i.Member.User.ID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(i.Member.User.ID)
-	if i.MessageComponentData().CustomID == PlexFixMissingEpPageNext {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+	if i.MessageComponentData().CustomID == withSession(PlexFixMissingEpPageNext, sess.ID) {
 		sess.EpisodePage++
 	} else if sess.EpisodePage > 0 {
 		sess.EpisodePage--
 	}
-	pfmStore.Set(i.Member.User.ID, *sess)
+	pfmStore.Set(sess.ID, *sess)
 	return pfmSendEpisodePage(s, sess)
 }
 
 func PlexFixMissingEpisodeSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	sess := pfmStore.Get(i.Member.User.ID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(i.Member.User.ID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+
+	release, ok := pfmSearchSlot(ctx, s, i)
+	if !ok {
+		return nil
+	}
+	defer release()
 
 	// Show "Now Searching"
 	searchingEmbeds := []*discordgo.MessageEmbed{ui.PlexFixMissingSearchingEmbed()}
@@ -484,21 +602,21 @@ func PlexFixMissingEpisodeSelectHandler(ctx *appctx.Context, s *discordgo.Sessio
 		Components: &[]discordgo.MessageComponent{}, // clear components
 	})
 
-	epID := i.MessageComponentData().Values[0]
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	epID, err := strconv.Atoi(i.MessageComponentData().Values[0])
+	if err != nil {
+		return nil
+	}
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
-	var releases []map[string]any
-	if err := ctx.Sonarr.Get(callCtx, "release?episodeId="+epID, &releases); err != nil {
+	releases, err := ctx.Sonarr.SearchEpisodeReleases(callCtx, epID)
+	if err != nil {
 		// If the fetch timed out (after 60s), abort the session with a friendly message
-		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(strings.ToLower(err.Error()), "timeout") {
-			seriesTitle, _ := sess.SelectedMedia["title"].(string)
+		if httpx.IsTimeout(err) {
+			seriesTitle := sess.SelectedMedia.Title
 			epDisplay := "Episode"
 			for _, ep := range sess.CurrentSeasonEps {
-				if fmt.Sprintf("%v", ep["id"]) == epID {
-					seF, _ := ep["seasonNumber"].(float64)
-					epF, _ := ep["episodeNumber"].(float64)
-					title, _ := ep["title"].(string)
-					epDisplay = fmt.Sprintf("S%02dE%02d - %s", int(seF), int(epF), title)
+				if ep.ID == epID {
+					epDisplay = ep.Label()
 					break
 				}
 			}
@@ -515,18 +633,27 @@ func PlexFixMissingEpisodeSelectHandler(ctx *appctx.Context, s *discordgo.Sessio
 				Embeds:     &abortEmbeds,
 				Components: &[]discordgo.MessageComponent{},
 			})
-			pfmStore.Clear(sess.UserID)
+			pfmStore.Clear(sess.ID)
 			return nil
 		}
 		_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("Fetch releases failed: " + err.Error())})
 		return nil
 	}
-	sess.Releases = releases
-	pfmStore.Set(sess.UserID, *sess)
+	sess.Releases = make([]pfmRelease, 0, len(releases))
+	for _, r := range releases {
+		sess.Releases = append(sess.Releases, pfmReleaseFromSonarr(r))
+	}
+	pfmStore.Set(sess.ID, *sess)
 	return pfmDisplayReleaseOptions(s, sess, false)
 }
 
-func pfmShowMovieReleases(ctx *appctx.Context, s *discordgo.Session, sess *pfmSession) error {
+func pfmShowMovieReleases(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, sess *pfmSession) error {
+	release, ok := pfmSearchSlot(ctx, s, i)
+	if !ok {
+		return nil
+	}
+	defer release()
+
 	// Show "Now Searching"
 	searchingEmbeds := []*discordgo.MessageEmbed{ui.PlexFixMissingSearchingEmbed()}
 	_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
@@ -536,15 +663,13 @@ func pfmShowMovieReleases(ctx *appctx.Context, s *discordgo.Session, sess *pfmSe
 		Components: &[]discordgo.MessageComponent{}, // clear components
 	})
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
-	movieID := fmt.Sprintf("%v", sess.SelectedMedia["id"])
-	var releases []map[string]any
-	if err := ctx.Radarr.Get(callCtx, "release?movieId="+movieID, &releases); err != nil {
+	releases, err := ctx.Radarr.SearchReleases(callCtx, sess.SelectedMedia.ID)
+	if err != nil {
 		// If the fetch timed out (after 60s), abort the session with a friendly message
-		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(strings.ToLower(err.Error()), "timeout") {
-			title, _ := sess.SelectedMedia["title"].(string)
-			msg := fmt.Sprintf("No files found for Media - %s", title)
+		if httpx.IsTimeout(err) {
+			msg := fmt.Sprintf("No files found for Media - %s", sess.SelectedMedia.Title)
 			abortEmbed := &discordgo.MessageEmbed{
 				Title:       "No results",
 				Description: msg,
@@ -557,71 +682,70 @@ func pfmShowMovieReleases(ctx *appctx.Context, s *discordgo.Session, sess *pfmSe
 				Embeds:     &abortEmbeds,
 				Components: &[]discordgo.MessageComponent{},
 			})
-			pfmStore.Clear(sess.UserID)
+			pfmStore.Clear(sess.ID)
 			return nil
 		}
 		_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("Fetch releases failed: " + err.Error())})
 		return nil
 	}
 	// filter valid
-	valid := make([]map[string]any, 0)
+	valid := make([]pfmRelease, 0)
+	all := make([]pfmRelease, 0, len(releases))
 	for _, r := range releases {
-		rej, _ := r["rejected"].(bool)
-		score, _ := r["customFormatScore"].(float64)
-		if !rej || score > 0 {
-			valid = append(valid, r)
+		rel := pfmReleaseFromRadarr(r)
+		all = append(all, rel)
+		if !rel.Rejected || rel.Score > 0 {
+			valid = append(valid, rel)
 		}
 	}
 	if len(valid) == 0 {
-		valid = releases
+		valid = all
 	}
 	sess.Releases = valid
-	pfmStore.Set(sess.UserID, *sess)
+	pfmStore.Set(sess.ID, *sess)
 	return pfmDisplayReleaseOptions(s, sess, true)
 }
 
+// pfmSearchSlot takes one of the limited release search slots. When all are
+// taken it tells the user and leaves the menu as it is, so they can retry.
+func pfmSearchSlot(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) (release func(), ok bool) {
+	release, ok = ctx.Limits.TrySearch()
+	if !ok {
+		ctx.Log.Info("release search slots full")
+		_, _ = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: limits.BusyMessage,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+	}
+	return release, ok
+}
+
 func pfmDisplayReleaseOptions(s *discordgo.Session, sess *pfmSession, isMovie bool) error {
 	opts := pfmBuildReleaseOptions(sess, isMovie, "")
 	rows := []discordgo.MessageComponent{
-		pfmSelectRow(PlexFixMissingSelectRelease, opts, "Select Release"),
-		pfmButtonsRow(discordgo.Button{Label: "Change", Style: discordgo.PrimaryButton, CustomID: PlexFixMissingChangeRelease}, pfmAbortBtn()),
+		pfmSelectRow(withSession(PlexFixMissingSelectRelease, sess.ID), opts, "Select Release"),
+		pfmButtonsRow(discordgo.Button{Label: "Change", Style: discordgo.PrimaryButton, CustomID: withSession(PlexFixMissingChangeRelease, sess.ID)}, pfmAbortBtn(sess.ID)),
 	}
-	embeds := []*discordgo.MessageEmbed{ui.PlexFixMissingReleaseEmbed(isMovie)}
-	_, err := s.InteractionResponseEdit(sess.Interaction, &discordgo.WebhookEdit{Embeds: &embeds, Components: &rows})
-	return err
+	return editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, ui.PlexFixMissingReleaseEmbed(isMovie), rows)
 }
 
 func pfmBuildReleaseOptions(sess *pfmSession, isMovie bool, selectedGUID string) []discordgo.SelectMenuOption {
-	// sort key
-	key := func(m map[string]any) float64 {
-		if v, ok := m["customFormatScore"].(float64); ok {
-			return v
-		}
-		if v, ok := m["qualityWeight"].(float64); ok {
-			return v
+	// sort by custom format score, then quality weight
+	sort.SliceStable(sess.Releases, func(i, j int) bool {
+		a, b := sess.Releases[i], sess.Releases[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
 		}
-		return 0
-	}
-	sort.SliceStable(sess.Releases, func(i, j int) bool { return key(sess.Releases[i]) > key(sess.Releases[j]) })
+		return a.QualityWeight > b.QualityWeight
+	})
 	opts := []discordgo.SelectMenuOption{}
 	for _, r := range sess.Releases {
-		approved, _ := r["approved"].(bool)
 		emoji := "❌"
-		if approved {
+		if r.Approved {
 			emoji = "✅"
 		}
-		title, _ := r["title"].(string)
-		if title == "" {
-			if mts, ok := r["movieTitles"].([]any); ok && len(mts) > 0 {
-				title, _ = mts[0].(string)
-			}
-		}
-		label := fmt.Sprintf("%s %s", emoji, title)
-		if len(label) > 100 {
-			label = label[:97] + "..."
-		}
-		guid := fmt.Sprintf("%v", r["guid"])
-		opts = append(opts, discordgo.SelectMenuOption{Label: label, Value: guid, Default: guid == selectedGUID})
+		label := fmt.Sprintf("%s %s", emoji, r.Title)
+		opts = append(opts, discordgo.SelectMenuOption{Label: ui.Truncate(label, 100), Value: r.GUID, Default: r.GUID == selectedGUID})
 		if len(opts) == 25 {
 			break
 		}
@@ -630,105 +754,125 @@ func pfmBuildReleaseOptions(sess *pfmSession, isMovie bool, selectedGUID string)
 }
 
 func PlexFixMissingReleaseSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	sess := pfmStore.Get(i.Member.User.ID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(i.Member.User.ID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
 	guid := i.MessageComponentData().Values[0]
-	var rel map[string]any
-	for _, r := range sess.Releases {
-		if fmt.Sprintf("%v", r["guid"]) == guid {
-			rel = r
+	var rel *pfmRelease
+	for idx := range sess.Releases {
+		if sess.Releases[idx].GUID == guid {
+			rel = &sess.Releases[idx]
 			break
 		}
 	}
 	if rel == nil {
 		_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("Release not found."), Components: &[]discordgo.MessageComponent{}})
-		pfmStore.Clear(sess.UserID)
+		pfmStore.Clear(sess.ID)
 		return nil
 	}
 	sess.SelectedRelease = rel
-	pfmStore.Set(sess.UserID, *sess)
+	pfmStore.Set(sess.ID, *sess)
 	// Build info embed
-	embed := ui.PlexFixMissingReleaseInfoEmbed(rel)
+	embed := ui.PlexFixMissingReleaseInfoEmbed(rel.ReleaseInfo)
 
 	rows := []discordgo.MessageComponent{
-		pfmSelectRow(PlexFixMissingSelectRelease, pfmBuildReleaseOptions(sess, sess.IsMovie, guid), "Select Release"),
-		pfmButtonsRow(discordgo.Button{Label: "Approve", Style: discordgo.SuccessButton, CustomID: PlexFixMissingApprove}, pfmAbortBtn()),
+		pfmSelectRow(withSession(PlexFixMissingSelectRelease, sess.ID), pfmBuildReleaseOptions(sess, sess.IsMovie, guid), "Select Release"),
+		pfmButtonsRow(discordgo.Button{Label: "Approve", Style: discordgo.SuccessButton, CustomID: withSession(PlexFixMissingApprove, sess.ID)}, pfmAbortBtn(sess.ID)),
 	}
-	_, _ = s.InteractionResponseEdit(sess.Interaction, &discordgo.WebhookEdit{Embeds: &[]*discordgo.MessageEmbed{embed}, Components: &rows})
+	_ = editSessionMessageSimple(s, sess.ChannelID, sess.MessageID, embed, rows)
 	return nil
 }
 
 func PlexFixMissingChangeReleaseHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	sess := pfmStore.Get(i.Member.User.ID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(i.Member.User.ID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
 	return pfmDisplayReleaseOptions(s, sess, sess.IsMovie)
 }
 
 func PlexFixMissingApproveHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	rel := sess.SelectedRelease
+	if rel != nil && rel.Rejected {
+		// Grabbing a release the Arr rejected is a separate, admin-only action by
+		// default, and needs a reason.
+		if !ctx.Perms.Can(i, permissions.FixMissingApproveRejected) {
+			return util.RespondEphemeral(s, i, fmt.Sprintf(permissions.DeniedMessage, "grab a rejected release"))
+		}
+		return showModal(s, i, withSession(PlexFixMissingOverrideModal, sess.ID), "Grab rejected release", discordgo.TextInput{
+			CustomID:    pfmOverrideReasonInput,
+			Label:       "Why grab it anyway?",
+			Style:       discordgo.TextInputParagraph,
+			Placeholder: ui.Truncate(strings.Join(rel.Rejections, "; "), 100),
+			Required:    true,
+			MaxLength:   500,
+		})
+	}
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-	sess := pfmStore.Get(i.Member.User.ID)
+	return pfmGrab(ctx, s, sess, "")
+}
+
+// PlexFixMissingOverrideHandler grabs a rejected release once the override
+// modal is submitted with a reason.
+func PlexFixMissingOverrideHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, pfmStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	pfmStore.Touch(i.Member.User.ID)
+	reason := modalValues(i)[pfmOverrideReasonInput]
+	if reason == "" {
+		return util.RespondEphemeral(s, i, "A reason is required to grab a rejected release.")
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+	return pfmGrab(ctx, s, sess, reason)
+}
+
+// pfmGrab sends the selected release to Radarr or Sonarr and ends the
+// session. reason is set when a rejected release is grabbed anyway.
+func pfmGrab(ctx *appctx.Context, s *discordgo.Session, sess *pfmSession, reason string) error {
 	rel := sess.SelectedRelease
 	if rel == nil {
 		_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("No release selected."), Components: &[]discordgo.MessageComponent{}})
-		pfmStore.Clear(sess.UserID)
+		pfmStore.Clear(sess.ID)
 		return nil
 	}
-	payload := map[string]any{
-		"guid":      rel["guid"],
-		"indexerId": rel["indexerId"],
-		"title": func() string {
-			if t, ok := rel["title"].(string); ok && t != "" {
-				return t
-			}
-			if mts, ok := rel["movieTitles"].([]any); ok && len(mts) > 0 {
-				if t, ok := mts[0].(string); ok {
-					return t
-				}
-			}
-			return ""
-		}(),
-		"protocol": rel["protocol"],
-	}
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
-	var out any
 	var err error
 	if sess.IsMovie {
-		err = ctx.Radarr.Post(callCtx, "release", payload, &out)
+		err = ctx.Radarr.GrabRelease(callCtx, rel.GUID, rel.IndexerID)
 	} else {
-		err = ctx.Sonarr.Post(callCtx, "release", payload, &out)
+		err = ctx.Sonarr.GrabRelease(callCtx, rel.GUID, rel.IndexerID)
 	}
 	if err != nil {
 		_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("Approve failed: " + err.Error())})
 		return nil
 	}
-	embeds := []*discordgo.MessageEmbed{ui.PlexFixMissingDownloadStartedEmbed(payload["title"].(string))}
+	embed := ui.PlexFixMissingDownloadStartedEmbed(rel.Title)
+	if reason != "" {
+		ctx.Log.Warn("rejected release grabbed", "release", rel.Title, "rejections", rel.Rejections, "reason", reason)
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Override reason", Value: ui.Truncate(reason, 1024)})
+	}
+	embeds := []*discordgo.MessageEmbed{embed}
 	_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Embeds: &embeds, Components: &[]discordgo.MessageComponent{}})
-	pfmStore.Clear(sess.UserID)
+	pfmStore.Clear(sess.ID)
 	return nil
 }
 
 func PlexFixMissingAbortHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	// allow only author or admin to abort
-	userID := i.Member.User.ID
-	sess := pfmStore.Get(userID)
+	sess := openSession(ctx, s, i, pfmStore, permissions.AbortAnySession)
 	if sess == nil {
 		return nil
 	}
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
 	_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: sess.MessageID, Channel: sess.ChannelID, Content: util.PtrString("Aborted."), Components: &[]discordgo.MessageComponent{}})
-	pfmStore.Clear(userID)
+	pfmStore.Clear(sess.ID)
 	return nil
 }
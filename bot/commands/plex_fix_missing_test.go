@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPfmFilterMedia(t *testing.T) {
+	items := []pfmMedia{
+		{ID: 1, Title: "The Expanse", Year: 2015},
+		{ID: 2, Title: "Expanse of Time", Year: 2020},
+		{ID: 123, Title: "Andor", Year: 2022},
+	}
+
+	tests := []struct {
+		name      string
+		mediaType string
+		q         string
+		want      []int
+	}{
+		{"title substring", "tv", "expanse", []int{1, 2}},
+		{"no title match", "tv", "dune", nil},
+		{"pick of the same type", "tv", "tv:123", []int{123}},
+		{"pick of the other type", "tv", "movie:123", nil},
+		{"pick not in library", "movie", "movie:9", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, m := range pfmFilterMedia(items, tt.mediaType, tt.q) {
+				got = append(got, m.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pfmFilterMedia(%q, %q) = %v, want %v", tt.mediaType, tt.q, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,9 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -12,19 +13,33 @@ import (
 
 	"github.com/KevinHaeusler/go-haruki/bot/appctx"
 	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
 	"github.com/KevinHaeusler/go-haruki/bot/session"
 	"github.com/KevinHaeusler/go-haruki/bot/ui"
 	"github.com/KevinHaeusler/go-haruki/bot/util"
 )
 
 const (
-	// PlexRoleID Required role to use /plex-request
-	PlexRoleID = "1228676841057816707"
-
 	PlexRequestSelectID  = "plex_request_select"
 	PlexRequestConfirmID = "plex_request_confirm"
-	PlexRequestAbortID   = "plex_request_abort"
-	PlexRequestNotifyID  = "plex_request_notify"
+	// PlexRequestConfirm4KID is Request 4K, offered to members allowed
+	// permissions.Request4K.
+	PlexRequestConfirm4KID = "plex_request_confirm_4k"
+	PlexRequestAbortID     = "plex_request_abort"
+	PlexRequestNotifyID    = "plex_request_notify"
+
+	// TV requests pick their seasons in a step between Request and sending it.
+	PlexRequestSeasonsID     = "plex_request_seasons"
+	PlexRequestSeasonsSendID = "plex_request_seasons_send"
+	PlexRequestAllSeasonsID  = "plex_request_seasons_all"
+
+	// Prev and Next page through the Jellyseerr search results.
+	PlexRequestPrevID = "plex_request_prev"
+	PlexRequestNextID = "plex_request_next"
+
+	// searchSkipPages is how many pages without a result of the wanted media
+	// type are skipped before giving up.
+	searchSkipPages = 5
 
 	// PlexSessionTTL Session TTL
 	PlexSessionTTL = 180 * time.Second
@@ -45,10 +60,11 @@ var PlexRequestCommand = &discordgo.ApplicationCommand{
 			},
 		},
 		{
-			Type:        discordgo.ApplicationCommandOptionString,
-			Name:        "media",
-			Description: "Media name to search",
-			Required:    true,
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "media",
+			Description:  "Media name to search",
+			Required:     true,
+			Autocomplete: true,
 		},
 	},
 }
@@ -56,40 +72,69 @@ var PlexRequestCommand = &discordgo.ApplicationCommand{
 // ---- session state ----
 
 type requestSession struct {
+	ID         string
 	UserID     string
 	MediaType  string
 	Query      string
 	Results    []jellyseerr.MediaSummary
 	SelectedID int
+	Is4k       bool // requesting the 4K tier
+
+	// Page and TotalPages of the search results; 0 when there is nothing to
+	// page through, e.g. for a title picked from autocomplete.
+	Page       int
+	TotalPages int
+
+	// TV only: the seasons offered in the season step and those picked so far.
+	SeasonChoices []jellyseerr.Season
+	Seasons       []int
+
+	// Advanced is set while the request goes through the Advanced step.
+	Advanced *requestAdvanced
 
 	ChannelID string
 	MessageID string
 }
 
 var (
-	requestStore = session.NewStore[requestSession](PlexSessionTTL)
+	requestStore = session.New[requestSession](nil, PlexRequestCommand.Name, PlexSessionTTL)
 )
 
+func (r requestSession) owner() string { return r.UserID }
+
+// requestOptions returns what to send along with the request.
+func (r requestSession) requestOptions(seasons []int) jellyseerr.RequestOptions {
+	opts := jellyseerr.RequestOptions{Seasons: seasons, Is4k: r.Is4k}
+	if a := r.Advanced; a != nil {
+		id := a.Options.Server.ID
+		opts.ServerID = &id
+		opts.ProfileID = a.ProfileID
+		opts.RootFolder = a.RootFolder
+		opts.LanguageProfileID = a.LanguageProfileID
+	}
+	return opts
+}
+
 // ---- slash handler ----
 
 func PlexRequestHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	if ctx.Jelly == nil {
-		return util.RespondEphemeral(s, i, "Jellyseerr is not configured.")
-	}
-
-	if !util.UserHasRole(i, PlexRoleID) {
-		return util.RespondEphemeral(s, i, "You need the Plex role to use `/plex-request`.")
+		return notRun(util.RespondEphemeral(s, i, "Jellyseerr is not configured."))
 	}
 
 	mt := strings.ToLower(strings.TrimSpace(util.GetOptString(i, "media-type")))
 	q := strings.TrimSpace(util.GetOptString(i, "media"))
-	log.Printf("[CMD] /plex-request invoked by %s (%s) guild=%s channel=%s media-type=%s query=%q", i.Member.User.Username, i.Member.User.ID, i.GuildID, i.ChannelID, mt, q)
+	ctx.Log.Info("plex-request invoked", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID, "media_type", mt, "query", q)
+
+	if pickType, pickID, ok := parseMediaPick(q); ok {
+		return plexRequestPicked(ctx, s, i, pickType, pickID)
+	}
 
 	if mt != "tv" && mt != "movie" {
-		return util.RespondEphemeral(s, i, "media-type must be `tv` or `movie`.")
+		return notRun(util.RespondEphemeral(s, i, "media-type must be `tv` or `movie`."))
 	}
 	if q == "" {
-		return util.RespondEphemeral(s, i, "media cannot be empty.")
+		return notRun(util.RespondEphemeral(s, i, "media cannot be empty."))
 	}
 
 	// Defer immediately (avoid Discord 3s timeout)
@@ -99,71 +144,187 @@ func PlexRequestHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.
 		return err
 	}
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
 
-	results, err := ctx.Jelly.SearchSummary(callCtx, q, mt)
+	page, err := plexRequestSearch(callCtx, ctx, q, mt, 1, 1)
 	if err != nil {
-		log.Printf("[CMD] /plex-request search error for %s (%s): %v", i.Member.User.Username, i.Member.User.ID, err)
+		ctx.Log.Error("plex-request search failed", "query", q, "err", err)
 		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: util.PtrString("Search failed: " + err.Error()),
 		})
-		return nil
+		return reported(err)
 	}
-	log.Printf("[CMD] /plex-request results=%d for query=%q", len(results), q)
-	if len(results) == 0 {
+	ctx.Log.Info("plex-request search results", "query", q, "count", len(page.Results), "page", page.Page, "total_pages", page.TotalPages)
+	if len(page.Results) == 0 {
 		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: util.PtrString(fmt.Sprintf("No results for `%s`.", q)),
 		})
-		return nil
+		return ErrNotRun
 	}
 
-	embed := ui.JellyResultListEmbed(q)
-
-	components := []discordgo.MessageComponent{
-		ui.ResultsSelect(PlexRequestSelectID, results, 0),
-		ui.ButtonsRow(ui.AbortButton(PlexRequestAbortID)),
+	sess := requestSession{
+		ID:         session.NewID(),
+		UserID:     i.Member.User.ID,
+		MediaType:  mt,
+		Query:      q,
+		Results:    page.Results,
+		Page:       page.Page,
+		TotalPages: page.TotalPages,
 	}
-
+	components := requestResultsComponents(&sess)
 	msg, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Embeds:     &[]*discordgo.MessageEmbed{ui.JellyResultListEmbed(q, sess.Page, sess.TotalPages)},
 		Components: &components,
 	})
 	if err != nil {
-		return nil
+		return err
 	}
 
-	userID := i.Member.User.ID
-	requestStore.Set(userID, requestSession{
-		UserID:     userID,
-		MediaType:  mt,
-		Query:      q,
-		Results:    results,
-		SelectedID: 0,
-		ChannelID:  msg.ChannelID,
-		MessageID:  msg.ID,
+	sess.ChannelID = msg.ChannelID
+	sess.MessageID = msg.ID
+	requestStore.Set(sess.ID, sess)
+	return nil
+}
+
+// plexRequestSearch returns the first page from page on, walking in the
+// direction of step, that has results of the media type. It gives up after
+// searchSkipPages pages or at either end and returns the last page loaded.
+func plexRequestSearch(callCtx context.Context, ctx *appctx.Context, q, mediaType string, page, step int) (jellyseerr.SearchPage, error) {
+	var res jellyseerr.SearchPage
+	for n := 0; n < searchSkipPages; n++ {
+		var err error
+		res, err = ctx.Jelly.Search(callCtx, q, mediaType, page)
+		if err != nil {
+			return jellyseerr.SearchPage{}, err
+		}
+		if len(res.Results) > 0 {
+			break
+		}
+		page += step
+		if page < 1 || page > res.TotalPages {
+			break
+		}
+	}
+	return res, nil
+}
+
+// requestResultsComponents is the results menu with paging and Abort.
+func requestResultsComponents(sess *requestSession) []discordgo.MessageComponent {
+	buttons := make([]discordgo.MessageComponent, 0, 3)
+	if sess.TotalPages > 1 {
+		buttons = append(buttons, ui.PageButtons(withSession(PlexRequestPrevID, sess.ID), withSession(PlexRequestNextID, sess.ID), sess.Page, sess.TotalPages)...)
+	}
+	buttons = append(buttons, ui.AbortButton(withSession(PlexRequestAbortID, sess.ID)))
+	return []discordgo.MessageComponent{
+		ui.ResultsSelect(withSession(PlexRequestSelectID, sess.ID), sess.Results, sess.SelectedID),
+		ui.ButtonsRow(buttons...),
+	}
+}
+
+// PlexRequestPageHandler shows the previous or next page of search results,
+// skipping pages without a result of the media type.
+func PlexRequestPageHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
 	})
+	step := 1
+	if i.MessageComponentData().CustomID == withSession(PlexRequestPrevID, sess.ID) {
+		step = -1
+	}
+	if sess.TotalPages <= 1 || sess.Page+step < 1 || sess.Page+step > sess.TotalPages {
+		return nil
+	}
 
-	go expireSession(s, userID, msg.ChannelID, msg.ID)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
+	defer cancel()
+
+	page, err := plexRequestSearch(callCtx, ctx, sess.Query, sess.MediaType, sess.Page+step, step)
+	if err != nil {
+		ctx.Log.Error("plex-request search failed", "query", sess.Query, "page", sess.Page+step, "err", err)
+		return util.FollowupEphemeral(s, i, "Search failed: "+err.Error())
+	}
+	ctx.Log.Info("plex-request page", "username", i.Member.User.Username, "query", sess.Query, "page", page.Page, "count", len(page.Results))
+	if len(page.Results) == 0 {
+		return util.FollowupEphemeral(s, i, "No more results.")
+	}
+
+	sess.Results = page.Results
+	sess.Page = page.Page
+	sess.TotalPages = page.TotalPages
+	sess.SelectedID = 0
+	requestStore.Set(sess.ID, *sess)
+	embed := ui.JellyResultListEmbed(sess.Query, sess.Page, sess.TotalPages)
+	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, requestResultsComponents(sess))
+}
+
+// plexRequestPicked handles a title picked from autocomplete: there is nothing
+// left to search, so the flow starts at the detail view.
+func plexRequestPicked(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, mediaType string, mediaID int) error {
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		return err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
+	defer cancel()
 
+	detail, err := ctx.Jelly.GetDetail(callCtx, mediaType, mediaID)
+	if err != nil {
+		ctx.Log.Error("plex-request load details failed", "media_type", mediaType, "media_id", mediaID, "err", err)
+		_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: util.PtrString("Failed to load details: " + err.Error()),
+		})
+		return reported(err)
+	}
+
+	sess := requestSession{
+		ID:        session.NewID(),
+		UserID:    i.Member.User.ID,
+		MediaType: mediaType,
+		Query:     detail.DisplayTitle(mediaType),
+		Results: []jellyseerr.MediaSummary{{
+			ID:        mediaID,
+			Title:     detail.DisplayTitle(mediaType),
+			Year:      detail.DisplayYear(mediaType),
+			MediaType: mediaType,
+		}},
+		SelectedID: mediaID,
+	}
+
+	quota := plexRequestQuota(callCtx, ctx, &sess)
+	components := requestDetailComponents(ctx, i, &sess, quota)
+	msg, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{ui.JellyDetailEmbed(detail, mediaType, ctx.Perms.Can(i, permissions.Request4K), quota)},
+		Components: &components,
+	})
+	if err != nil {
+		return err
+	}
+
+	sess.ChannelID = msg.ChannelID
+	sess.MessageID = msg.ID
+	requestStore.Set(sess.ID, sess)
 	return nil
 }
 
 // ---- component handlers ----
 
 func PlexRequestSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
 	// Acknowledge component interaction immediately
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredMessageUpdate,
 	})
-	log.Printf("[CMD] /plex-request select by %s (%s) guild=%s channel=%s", i.Member.User.Username, i.Member.User.ID, i.GuildID, i.ChannelID)
-
-	userID := i.Member.User.ID
-	sess := requestStore.Get(userID)
-	if sess == nil {
-		return nil
-	}
-	requestStore.Touch(userID)
+	ctx.Log.Info("plex-request select", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID)
 
 	vals := i.MessageComponentData().Values
 	if len(vals) == 0 {
@@ -174,12 +335,16 @@ func PlexRequestSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *disc
 	if err != nil {
 		return nil
 	}
-	log.Printf("[CMD] /plex-request selected mediaID=%d by %s (%s)", selectedID, i.Member.User.Username, i.Member.User.ID)
+	ctx.Log.Info("plex-request selected", "media_id", selectedID)
 
 	sess.SelectedID = selectedID
-	requestStore.Set(userID, *sess)
+	requestStore.Set(sess.ID, *sess)
+	return plexRequestShowDetail(ctx, s, i, sess)
+}
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+// plexRequestShowDetail shows the selected title with the Request buttons.
+func plexRequestShowDetail(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, sess *requestSession) error {
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
 
 	detail, err := ctx.Jelly.GetDetail(callCtx, sess.MediaType, sess.SelectedID)
@@ -187,53 +352,107 @@ func PlexRequestSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *disc
 		return editSessionMessage(s, sess, "Failed to load details: "+err.Error(), nil, nil)
 	}
 
-	embed := ui.JellyDetailEmbed(detail, sess.MediaType)
+	quota := plexRequestQuota(callCtx, ctx, sess)
+	embed := ui.JellyDetailEmbed(detail, sess.MediaType, ctx.Perms.Can(i, permissions.Request4K), quota)
+	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, requestDetailComponents(ctx, i, sess, quota))
+}
 
-	components := []discordgo.MessageComponent{
-		ui.ResultsSelect(PlexRequestSelectID, sess.Results, sess.SelectedID),
-		ui.ButtonsRow(
-			ui.ConfirmButton(PlexRequestConfirmID),
-			ui.AbortButton(PlexRequestAbortID),
-		),
+// plexRequestQuota returns the session owner's quota for the media type, or
+// nil if it is unlimited or can't be looked up.
+func plexRequestQuota(callCtx context.Context, ctx *appctx.Context, sess *requestSession) *jellyseerr.Quota {
+	jellyID, err := ctx.Jelly.DiscordUserToJellyseerrUserID(callCtx, sess.UserID)
+	if err != nil || jellyID == 0 {
+		return nil // reported when they press Request
 	}
+	q, err := ctx.Jelly.GetUserQuota(callCtx, jellyID)
+	if err != nil {
+		ctx.Log.Debug("plex-request load quota failed", "jelly_user", jellyID, "err", err)
+		return nil
+	}
+	quota := q.For(sess.MediaType)
+	if quota.Limit == 0 {
+		return nil
+	}
+	return &quota
+}
 
-	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, components)
+// requestDetailComponents is the results menu plus Request and Abort, shown
+// once a title is selected, and paging if there is more than one page.
+// Request 4K and Advanced are only offered to members allowed to use them.
+// The request buttons are disabled while the quota is used up.
+func requestDetailComponents(ctx *appctx.Context, i *discordgo.InteractionCreate, sess *requestSession, quota *jellyseerr.Quota) []discordgo.MessageComponent {
+	exhausted := quota != nil && quota.Restricted
+	confirm := ui.ConfirmButton(withSession(PlexRequestConfirmID, sess.ID))
+	confirm.Disabled = exhausted
+	buttons := []discordgo.MessageComponent{confirm}
+	if ctx.Perms.Can(i, permissions.Request4K) {
+		confirm4K := ui.Confirm4KButton(withSession(PlexRequestConfirm4KID, sess.ID))
+		confirm4K.Disabled = exhausted
+		buttons = append(buttons, confirm4K)
+	}
+	if ctx.Perms.Can(i, permissions.RequestAdvanced) {
+		buttons = append(buttons, discordgo.Button{Label: "Advanced", Style: discordgo.SecondaryButton, CustomID: withSession(PlexRequestAdvancedID, sess.ID), Disabled: exhausted})
+	}
+	buttons = append(buttons, ui.AbortButton(withSession(PlexRequestAbortID, sess.ID)))
+	rows := []discordgo.MessageComponent{
+		ui.ResultsSelect(withSession(PlexRequestSelectID, sess.ID), sess.Results, sess.SelectedID),
+		ui.ButtonsRow(buttons...),
+	}
+	if sess.TotalPages > 1 {
+		rows = append(rows, ui.ButtonsRow(ui.PageButtons(withSession(PlexRequestPrevID, sess.ID), withSession(PlexRequestNextID, sess.ID), sess.Page, sess.TotalPages)...))
+	}
+	return rows
 }
 
 func PlexRequestConfirmHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredMessageUpdate,
-	})
-	log.Printf("[CMD] /plex-request confirm by %s (%s) guild=%s channel=%s", i.Member.User.Username, i.Member.User.ID, i.GuildID, i.ChannelID)
-
-	userID := i.Member.User.ID
-	sess := requestStore.Get(userID)
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
 	if sess == nil {
 		return nil
 	}
-	requestStore.Touch(userID)
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	sess.Is4k = i.MessageComponentData().CustomID == withSession(PlexRequestConfirm4KID, sess.ID)
+	sess.Advanced = nil
+	requestStore.Set(sess.ID, *sess)
+	ctx.Log.Info("plex-request confirm", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID, "4k", sess.Is4k)
+	return plexRequestConfirm(ctx, s, i, sess)
+}
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+// plexRequestConfirm checks the selected title's status and either requests
+// it, moves on to the season step, or explains why it can't be requested.
+func plexRequestConfirm(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, sess *requestSession) error {
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
 
 	detail, err := ctx.Jelly.GetDetail(callCtx, sess.MediaType, sess.SelectedID)
 	if err != nil {
-		log.Printf("[CMD] /plex-request confirm load details error: %v", err)
+		ctx.Log.Error("plex-request load details failed", "err", err)
 		return editSessionMessage(s, sess, "Failed to load details: "+err.Error(), nil, nil)
 	}
 
-	status := detail.MediaInfo.Status
-	log.Printf("[CMD] /plex-request media status=%d mediaType=%s mediaID=%d", status, sess.MediaType, sess.SelectedID)
+	status := detail.MediaInfo.StatusFor(sess.Is4k)
+	ctx.Log.Info("plex-request media status", "status", status, "media_type", sess.MediaType, "media_id", sess.SelectedID, "4k", sess.Is4k)
+
+	// TV: as long as some seasons are left, let the user choose which.
+	if sess.MediaType == "tv" {
+		if seasons := detail.RequestableSeasons(sess.Is4k); len(seasons) > 0 {
+			sess.SeasonChoices = seasons
+			sess.Seasons = nil
+			requestStore.Set(sess.ID, *sess)
+			return plexRequestShowSeasons(s, sess, detail)
+		}
+	}
 
 	// 2 or 3 => already requested
 	if status == 2 || status == 3 {
-		log.Printf("[CMD] /plex-request already requested by someone else. user=%s (%s) mediaID=%d", i.Member.User.Username, i.Member.User.ID, sess.SelectedID)
+		ctx.Log.Info("plex-request already requested by someone else", "media_id", sess.SelectedID)
 		embed := ui.JellyAlreadyRequestedEmbed(detail, sess.MediaType)
 
 		comps := []discordgo.MessageComponent{
 			ui.ButtonsRow(
-				ui.NotifyButton(PlexRequestNotifyID),
-				ui.AbortButton(PlexRequestAbortID),
+				ui.NotifyButton(withSession(PlexRequestNotifyID, sess.ID)),
+				ui.AbortButton(withSession(PlexRequestAbortID, sess.ID)),
 			),
 		}
 
@@ -242,13 +461,13 @@ func PlexRequestConfirmHandler(ctx *appctx.Context, s *discordgo.Session, i *dis
 
 	// 4 = partial availability -> show requester + notify button (NOT terminal)
 	if status == 4 {
-		log.Printf("[CMD] /plex-request partial availability for mediaID=%d", sess.SelectedID)
+		ctx.Log.Info("plex-request partially available", "media_id", sess.SelectedID)
 		embed := ui.JellyPartialAvailabilityEmbed(detail, sess.MediaType)
 
 		comps := []discordgo.MessageComponent{
 			ui.ButtonsRow(
-				ui.NotifyButton(PlexRequestNotifyID),
-				ui.AbortButton(PlexRequestAbortID),
+				ui.NotifyButton(withSession(PlexRequestNotifyID, sess.ID)),
+				ui.AbortButton(withSession(PlexRequestAbortID, sess.ID)),
 			),
 		}
 
@@ -257,25 +476,122 @@ func PlexRequestConfirmHandler(ctx *appctx.Context, s *discordgo.Session, i *dis
 
 	// 5 = already available -> terminal
 	if status == 5 {
-		log.Printf("[CMD] /plex-request already available mediaID=%d", sess.SelectedID)
-		requestStore.Clear(userID)
+		ctx.Log.Info("plex-request already available", "media_id", sess.SelectedID)
+		requestStore.Clear(sess.ID)
 		embed := ui.JellyAvailabilityEmbed(detail, sess.MediaType, status)
 		return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
 	}
 
-	overseerrUserID, err := ctx.Jelly.DiscordUserToJellyseerrUserID(callCtx, userID)
+	return plexRequestSubmit(ctx, callCtx, s, i, sess, detail, sess.requestOptions(nil))
+}
+
+// plexRequestShowSeasons shows the season step of a TV request.
+func plexRequestShowSeasons(s *discordgo.Session, sess *requestSession, detail jellyseerr.MediaDetail) error {
+	embed := ui.JellySeasonPickEmbed(detail, len(sess.SeasonChoices), sess.Is4k)
+	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, requestSeasonComponents(sess))
+}
+
+func requestSeasonComponents(sess *requestSession) []discordgo.MessageComponent {
+	send := ui.ConfirmButton(withSession(PlexRequestSeasonsSendID, sess.ID))
+	send.Disabled = len(sess.Seasons) == 0
+	return []discordgo.MessageComponent{
+		ui.SeasonSelect(withSession(PlexRequestSeasonsID, sess.ID), sess.SeasonChoices, sess.Seasons),
+		ui.ButtonsRow(
+			send,
+			discordgo.Button{Label: "All Seasons", Style: discordgo.PrimaryButton, CustomID: withSession(PlexRequestAllSeasonsID, sess.ID)},
+			ui.AbortButton(withSession(PlexRequestAbortID, sess.ID)),
+		),
+	}
+}
+
+// PlexRequestSeasonsHandler remembers the seasons picked in the season menu.
+func PlexRequestSeasonsHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	seasons := make([]int, 0, len(i.MessageComponentData().Values))
+	for _, v := range i.MessageComponentData().Values {
+		if n, err := strconv.Atoi(v); err == nil {
+			seasons = append(seasons, n)
+		}
+	}
+	sort.Ints(seasons)
+	sess.Seasons = seasons
+	requestStore.Set(sess.ID, *sess)
+	return editSessionMessage(s, sess, "", nil, requestSeasonComponents(sess))
+}
+
+// PlexRequestSeasonsSendHandler requests the picked seasons, or all that are
+// left for All Seasons.
+func PlexRequestSeasonsSendHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	all := i.MessageComponentData().CustomID == withSession(PlexRequestAllSeasonsID, sess.ID)
+	ctx.Log.Info("plex-request seasons", "username", i.Member.User.Username, "seasons", sess.Seasons, "all", all)
+
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
+	defer cancel()
+
+	detail, err := ctx.Jelly.GetDetail(callCtx, sess.MediaType, sess.SelectedID)
 	if err != nil {
-		log.Printf("[CMD] /plex-request error mapping Discord->Jellyseerr for %s (%s): %v", i.Member.User.Username, i.Member.User.ID, err)
+		ctx.Log.Error("plex-request load details failed", "err", err)
+		return editSessionMessage(s, sess, "Failed to load details: "+err.Error(), nil, nil)
+	}
+
+	// Someone may have requested some of them since the menu was shown.
+	requestable := detail.RequestableSeasons(sess.Is4k)
+	picked := make(map[int]bool, len(sess.Seasons))
+	for _, n := range sess.Seasons {
+		picked[n] = true
+	}
+	var seasons []int
+	for _, season := range requestable {
+		if all || picked[season.SeasonNumber] {
+			seasons = append(seasons, season.SeasonNumber)
+		}
+	}
+	if len(seasons) == 0 {
+		if len(requestable) == 0 {
+			requestStore.Clear(sess.ID)
+			embed := ui.JellyAlreadyRequestedEmbed(detail, sess.MediaType)
+			return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
+		}
+		sess.SeasonChoices = requestable
+		sess.Seasons = nil
+		requestStore.Set(sess.ID, *sess)
+		return plexRequestShowSeasons(s, sess, detail)
+	}
+
+	return plexRequestSubmit(ctx, callCtx, s, i, sess, detail, sess.requestOptions(seasons))
+}
+
+// plexRequestSubmit sends the request as the session owner's Jellyseerr user
+// and shows the result. It ends the session once the request went through.
+func plexRequestSubmit(ctx *appctx.Context, callCtx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, sess *requestSession, detail jellyseerr.MediaDetail, opts jellyseerr.RequestOptions) error {
+	overseerrUserID, err := ctx.Jelly.DiscordUserToJellyseerrUserID(callCtx, sess.UserID)
+	if err != nil {
+		ctx.Log.Error("plex-request mapping Discord to Jellyseerr user failed", "err", err)
 		return editSessionMessage(s, sess, "Failed to link your Discord ID in Overseerr.", nil, nil)
 	}
 	if overseerrUserID == 0 {
-		log.Printf("[CMD] /plex-request no Jellyseerr link for %s (%s)", i.Member.User.Username, i.Member.User.ID)
+		ctx.Log.Info("plex-request user has no Jellyseerr link")
 		return editSessionMessage(s, sess, "Your Discord ID is not linked in Overseerr.", nil, nil)
 	}
 
-	if detail.HasRequester(overseerrUserID) {
-		log.Printf("[CMD] /plex-request user already requester jellyUserID=%d discordUser=%s (%s) mediaID=%d", overseerrUserID, i.Member.User.Username, i.Member.User.ID, sess.SelectedID)
-		requestStore.Clear(userID)
+	// Season requests are checked per season by Jellyseerr itself.
+	if len(opts.Seasons) == 0 && detail.HasTierRequester(overseerrUserID, opts.Is4k) {
+		ctx.Log.Info("plex-request user already requested this", "jelly_user", overseerrUserID, "media_id", sess.SelectedID)
+		requestStore.Clear(sess.ID)
 		embed := &discordgo.MessageEmbed{
 			Title:       "ℹ️ Already Requested",
 			Description: "You’ve already requested this media.",
@@ -283,40 +599,32 @@ func PlexRequestConfirmHandler(ctx *appctx.Context, s *discordgo.Session, i *dis
 		return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
 	}
 
-	resp, err := ctx.Jelly.RequestMedia(callCtx, sess.MediaType, sess.SelectedID, overseerrUserID)
+	resp, err := ctx.Jelly.RequestMedia(callCtx, sess.MediaType, sess.SelectedID, overseerrUserID, opts)
 	if err != nil {
-		log.Printf("[CMD] /plex-request request failed jellyUserID=%d mediaType=%s mediaID=%d err=%v", overseerrUserID, sess.MediaType, sess.SelectedID, err)
-		return editSessionMessage(s, sess, "Request failed: "+err.Error(), nil, nil)
+		ctx.Log.Error("plex-request request failed", "jelly_user", overseerrUserID, "media_type", sess.MediaType, "media_id", sess.SelectedID, "err", err)
+		return editSessionMessage(s, sess, requestFailedMessage("Request failed: ", err), nil, nil)
 	}
 
-	log.Printf("[CMD] /plex-request sent by %s (%s) jellyUserID=%d mediaType=%s mediaID=%d", i.Member.User.Username, i.Member.User.ID, overseerrUserID, sess.MediaType, sess.SelectedID)
-	requestStore.Clear(userID)
+	ctx.Log.Info("plex-request sent", "jelly_user", overseerrUserID, "media_type", sess.MediaType, "media_id", sess.SelectedID, "seasons", opts.Seasons)
+	requestStore.Clear(sess.ID)
 
 	total := resp.RequestedBy.RequestCount + 1
-	embed := ui.JellyRequestSentEmbed(detail, sess.MediaType, i.Member.User.Username, total)
+	embed := ui.JellyRequestSentEmbed(detail, sess.MediaType, i.Member.User.Username, total, opts)
 
 	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
 }
 
 func PlexRequestAbortHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
-	_ = ctx
-
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredMessageUpdate,
-	})
-	log.Printf("[CMD] /plex-request abort by %s (%s) guild=%s channel=%s", i.Member.User.Username, i.Member.User.ID, i.GuildID, i.ChannelID)
-
-	userID := i.Member.User.ID
-	sess := requestStore.Get(userID)
+	sess := openSession(ctx, s, i, requestStore, permissions.AbortAnySession)
 	if sess == nil {
 		return nil
 	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	ctx.Log.Info("plex-request abort", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID)
 
-	if userID != sess.UserID && !util.UserIsAdmin(i) {
-		return nil
-	}
-
-	requestStore.Clear(userID)
+	requestStore.Clear(sess.ID)
 
 	embed := &discordgo.MessageEmbed{
 		Title:       "Aborted",
@@ -326,29 +634,13 @@ func PlexRequestAbortHandler(ctx *appctx.Context, s *discordgo.Session, i *disco
 	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
 }
 
-func expireSession(s *discordgo.Session, userID, channelID, messageID string) {
-	for {
-		time.Sleep(10 * time.Second)
-		_, expired := requestStore.GetWithExpiration(userID)
-		if expired {
-			embed := &discordgo.MessageEmbed{
-				Title:       "Aborted",
-				Description: "Session timed out after 3 minutes of inactivity.",
-				Color:       0xff0000,
-			}
-			embeds := []*discordgo.MessageEmbed{embed}
-			_, _ = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-				ID:         messageID,
-				Channel:    channelID,
-				Embeds:     &embeds,
-				Components: &[]discordgo.MessageComponent{},
-			})
-			return
-		}
-		if requestStore.Get(userID) == nil {
-			return
-		}
+// requestFailedMessage explains a failed RequestMedia call; other errors are
+// shown after prefix.
+func requestFailedMessage(prefix string, err error) string {
+	if errors.Is(err, jellyseerr.ErrQuotaExceeded) {
+		return "🚫 You've used up your request quota. Try again once older requests fall out of the quota window."
 	}
+	return prefix + err.Error()
 }
 
 func editSessionMessage(s *discordgo.Session, sess *requestSession, content string, embeds []*discordgo.MessageEmbed, comps []discordgo.MessageComponent) error {
@@ -373,28 +665,27 @@ func editSessionMessage(s *discordgo.Session, sess *requestSession, content stri
 }
 
 func PlexRequestNotifyHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredMessageUpdate,
 	})
-
-	userID := i.Member.User.ID
-	sess := requestStore.Get(userID)
-
-	if sess == nil || sess.SelectedID == 0 {
+	if sess.SelectedID == 0 {
 		return nil
 	}
-	requestStore.Touch(userID)
 
-	callCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
 
-	overID, err := ctx.Jelly.DiscordUserToJellyseerrUserID(callCtx, userID)
+	overID, err := ctx.Jelly.DiscordUserToJellyseerrUserID(callCtx, sess.UserID)
 	if err != nil || overID == 0 {
 		embed := &discordgo.MessageEmbed{
 			Title:       "Not linked",
 			Description: "Your Discord ID is not linked in Jellyseerr.",
 		}
-		requestStore.Clear(userID)
+		requestStore.Clear(sess.ID)
 		return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
 	}
 
@@ -408,13 +699,13 @@ func PlexRequestNotifyHandler(ctx *appctx.Context, s *discordgo.Session, i *disc
 			Title:       "ℹ️ Already Requested",
 			Description: "You’ll be notified (already on the watcher list).",
 		}
-		requestStore.Clear(userID)
+		requestStore.Clear(sess.ID)
 		return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
 	}
 
-	_, err = ctx.Jelly.RequestMedia(callCtx, sess.MediaType, sess.SelectedID, overID)
+	_, err = ctx.Jelly.RequestMedia(callCtx, sess.MediaType, sess.SelectedID, overID, jellyseerr.RequestOptions{Is4k: sess.Is4k})
 	if err != nil {
-		return editSessionMessage(s, sess, "Notify request failed: "+err.Error(), nil, nil)
+		return editSessionMessage(s, sess, requestFailedMessage("Notify request failed: ", err), nil, nil)
 	}
 
 	embed := &discordgo.MessageEmbed{
@@ -422,6 +713,6 @@ func PlexRequestNotifyHandler(ctx *appctx.Context, s *discordgo.Session, i *disc
 		Description: "You'll be notified when this item becomes available.",
 	}
 
-	requestStore.Clear(userID)
+	requestStore.Clear(sess.ID)
 	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{})
 }
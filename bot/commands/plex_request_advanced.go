@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
+	"github.com/KevinHaeusler/go-haruki/bot/ui"
+	"github.com/KevinHaeusler/go-haruki/bot/util"
+)
+
+// The Advanced step of /plex-request picks the Radarr/Sonarr server, quality
+// profile, root folder and (Sonarr v3) language profile of a request.
+const (
+	PlexRequestAdvancedID     = "plex_request_advanced"
+	PlexRequestServerID       = "plex_request_adv_server"
+	PlexRequestProfileID      = "plex_request_adv_profile"
+	PlexRequestRootFolderID   = "plex_request_adv_folder"
+	PlexRequestLanguageID     = "plex_request_adv_language"
+	PlexRequestAdvancedSendID = "plex_request_adv_send"
+	PlexRequestBackID         = "plex_request_back"
+)
+
+// requestAdvanced is what the Advanced step has loaded and picked so far.
+type requestAdvanced struct {
+	Servers []jellyseerr.Server
+	Options jellyseerr.ServerOptions // of the selected server
+
+	ProfileID         int
+	RootFolder        string
+	LanguageProfileID int
+}
+
+// newRequestAdvanced starts out with the server's own defaults.
+func newRequestAdvanced(servers []jellyseerr.Server, opts jellyseerr.ServerOptions) *requestAdvanced {
+	return &requestAdvanced{
+		Servers:           servers,
+		Options:           opts,
+		ProfileID:         opts.Server.ActiveProfileID,
+		RootFolder:        opts.Server.ActiveDirectory,
+		LanguageProfileID: opts.Server.ActiveLanguageProfileID,
+	}
+}
+
+// PlexRequestAdvancedHandler opens the Advanced step on the default server.
+func PlexRequestAdvancedHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	ctx.Log.Info("plex-request advanced", "username", i.Member.User.Username, "media_type", sess.MediaType, "media_id", sess.SelectedID)
+
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
+	defer cancel()
+
+	all, err := ctx.Jelly.ListServers(callCtx, sess.MediaType)
+	if err != nil {
+		ctx.Log.Error("plex-request list servers failed", "err", err)
+		return util.FollowupEphemeral(s, i, "Failed to load servers: "+err.Error())
+	}
+	can4K := ctx.Perms.Can(i, permissions.Request4K)
+	servers := make([]jellyseerr.Server, 0, len(all))
+	for _, srv := range all {
+		if !srv.Is4k || can4K {
+			servers = append(servers, srv)
+		}
+	}
+	if len(servers) == 0 {
+		return util.FollowupEphemeral(s, i, "No servers for this media type are configured in Jellyseerr.")
+	}
+
+	def := servers[0]
+	for _, srv := range servers {
+		if srv.IsDefault && !srv.Is4k {
+			def = srv
+			break
+		}
+	}
+	opts, err := ctx.Jelly.GetServerOptions(callCtx, sess.MediaType, def.ID)
+	if err != nil {
+		ctx.Log.Error("plex-request load server options failed", "server", def.ID, "err", err)
+		return util.FollowupEphemeral(s, i, "Failed to load server options: "+err.Error())
+	}
+
+	sess.Advanced = newRequestAdvanced(servers, opts)
+	requestStore.Set(sess.ID, *sess)
+	return plexRequestShowAdvanced(s, sess)
+}
+
+// PlexRequestServerHandler switches to another server and its defaults.
+func PlexRequestServerHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	a := sess.Advanced
+	vals := i.MessageComponentData().Values
+	if a == nil || len(vals) == 0 {
+		return nil
+	}
+	id, err := strconv.Atoi(vals[0])
+	if err != nil {
+		return nil
+	}
+	known := false
+	for _, srv := range a.Servers {
+		known = known || srv.ID == id
+	}
+	if !known {
+		return nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
+	defer cancel()
+
+	opts, err := ctx.Jelly.GetServerOptions(callCtx, sess.MediaType, id)
+	if err != nil {
+		ctx.Log.Error("plex-request load server options failed", "server", id, "err", err)
+		return util.FollowupEphemeral(s, i, "Failed to load server options: "+err.Error())
+	}
+	sess.Advanced = newRequestAdvanced(a.Servers, opts)
+	requestStore.Set(sess.ID, *sess)
+	return plexRequestShowAdvanced(s, sess)
+}
+
+// PlexRequestAdvancedSelectHandler remembers the quality profile, root
+// folder or language profile picked.
+func PlexRequestAdvancedSelectHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	a := sess.Advanced
+	vals := i.MessageComponentData().Values
+	if a == nil || len(vals) == 0 {
+		return nil
+	}
+	id, err := strconv.Atoi(vals[0])
+	if err != nil {
+		return nil
+	}
+
+	switch i.MessageComponentData().CustomID {
+	case withSession(PlexRequestProfileID, sess.ID):
+		a.ProfileID = id
+	case withSession(PlexRequestLanguageID, sess.ID):
+		a.LanguageProfileID = id
+	case withSession(PlexRequestRootFolderID, sess.ID):
+		// paths can exceed the 100 character option value limit, so the
+		// menu carries the folder ID
+		for _, f := range a.Options.RootFolders {
+			if f.ID == id {
+				a.RootFolder = f.Path
+			}
+		}
+	}
+	requestStore.Set(sess.ID, *sess)
+	return editSessionMessage(s, sess, "", nil, requestAdvancedComponents(sess))
+}
+
+// PlexRequestAdvancedSendHandler requests the title with the picked options.
+// The server decides whether it is a 4K request.
+func PlexRequestAdvancedSendHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if sess.Advanced == nil {
+		return nil
+	}
+	sess.Is4k = sess.Advanced.Options.Server.Is4k
+	if sess.Is4k && !ctx.Perms.Can(i, permissions.Request4K) {
+		return util.FollowupEphemeral(s, i, fmt.Sprintf(permissions.DeniedMessage, "request 4K"))
+	}
+	requestStore.Set(sess.ID, *sess)
+	ctx.Log.Info("plex-request confirm", "username", i.Member.User.Username, "guild", i.GuildID, "channel", i.ChannelID, "4k", sess.Is4k,
+		"server", sess.Advanced.Options.Server.ID, "profile", sess.Advanced.ProfileID, "root_folder", sess.Advanced.RootFolder)
+	return plexRequestConfirm(ctx, s, i, sess)
+}
+
+// PlexRequestBackHandler leaves the Advanced step for the detail view.
+func PlexRequestBackHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	sess := openSession(ctx, s, i, requestStore, permissions.UseAnySession)
+	if sess == nil {
+		return nil
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	sess.Advanced = nil
+	requestStore.Set(sess.ID, *sess)
+	return plexRequestShowDetail(ctx, s, i, sess)
+}
+
+func plexRequestShowAdvanced(s *discordgo.Session, sess *requestSession) error {
+	title := ""
+	for _, r := range sess.Results {
+		if r.ID == sess.SelectedID {
+			title = r.Title
+		}
+	}
+	embed := ui.JellyAdvancedEmbed(title, sess.Advanced.Options.Server)
+	return editSessionMessage(s, sess, "", []*discordgo.MessageEmbed{embed}, requestAdvancedComponents(sess))
+}
+
+// requestAdvancedComponents has one menu per option, skipping those the
+// server doesn't offer, and Request, Back and Abort.
+func requestAdvancedComponents(sess *requestSession) []discordgo.MessageComponent {
+	a := sess.Advanced
+	rows := make([]discordgo.MessageComponent, 0, 5)
+
+	servers := make([]discordgo.SelectMenuOption, 0, len(a.Servers))
+	for _, srv := range a.Servers[:min(25, len(a.Servers))] {
+		label := srv.Name
+		if srv.Is4k {
+			label += " (4K)"
+		}
+		servers = append(servers, discordgo.SelectMenuOption{
+			Label:   ui.Truncate(label, 100),
+			Value:   strconv.Itoa(srv.ID),
+			Default: srv.ID == a.Options.Server.ID,
+		})
+	}
+	rows = append(rows, ui.SelectMenu(withSession(PlexRequestServerID, sess.ID), "Server", servers))
+
+	if opts := profileOptions(a.Options.Profiles, a.ProfileID); len(opts) > 0 {
+		rows = append(rows, ui.SelectMenu(withSession(PlexRequestProfileID, sess.ID), "Quality profile", opts))
+	}
+	if len(a.Options.RootFolders) > 0 {
+		folders := make([]discordgo.SelectMenuOption, 0, len(a.Options.RootFolders))
+		for _, f := range a.Options.RootFolders[:min(25, len(a.Options.RootFolders))] {
+			folders = append(folders, discordgo.SelectMenuOption{
+				Label:       ui.Truncate(f.Path, 100),
+				Value:       strconv.Itoa(f.ID),
+				Description: fmt.Sprintf("%.2f GB free", float64(f.FreeSpace)/(1024*1024*1024)),
+				Default:     f.Path == a.RootFolder,
+			})
+		}
+		rows = append(rows, ui.SelectMenu(withSession(PlexRequestRootFolderID, sess.ID), "Root folder", folders))
+	}
+	if opts := profileOptions(a.Options.LanguageProfiles, a.LanguageProfileID); len(opts) > 0 {
+		rows = append(rows, ui.SelectMenu(withSession(PlexRequestLanguageID, sess.ID), "Language profile", opts))
+	}
+
+	rows = append(rows, ui.ButtonsRow(
+		ui.ConfirmButton(withSession(PlexRequestAdvancedSendID, sess.ID)),
+		discordgo.Button{Label: "Back", Style: discordgo.SecondaryButton, CustomID: withSession(PlexRequestBackID, sess.ID)},
+		ui.AbortButton(withSession(PlexRequestAbortID, sess.ID)),
+	))
+	return rows
+}
+
+func profileOptions(profiles []jellyseerr.QualityProfile, selected int) []discordgo.SelectMenuOption {
+	opts := make([]discordgo.SelectMenuOption, 0, min(25, len(profiles)))
+	for _, p := range profiles[:min(25, len(profiles))] {
+		opts = append(opts, discordgo.SelectMenuOption{
+			Label:   ui.Truncate(p.Name, 100),
+			Value:   strconv.Itoa(p.ID),
+			Default: p.ID == selected,
+		})
+	}
+	return opts
+}
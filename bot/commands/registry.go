@@ -1,48 +1,106 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
 	"github.com/bwmarrin/discordgo"
 )
 
 type Handler func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error
 type ComponentHandler func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error
 
+// ErrNotRun is returned by a slash handler that has refused the request and
+// told the user why, e.g. bad options or nothing found. The run doesn't
+// count against the cooldown and isn't an error.
+var ErrNotRun = errors.New("command not run")
+
+// notRun returns err if replying to the user failed, ErrNotRun otherwise.
+func notRun(err error) error {
+	if err != nil {
+		return err
+	}
+	return ErrNotRun
+}
+
+// ReportedError is a failure the handler has already shown the user, such
+// as an upstream error in its deferred reply. It still counts as a failed
+// run, but the user isn't told a second time.
+type ReportedError struct {
+	Err error
+}
+
+func (e *ReportedError) Error() string { return e.Err.Error() }
+func (e *ReportedError) Unwrap() error { return e.Err }
+
+// reported marks err as already shown to the user.
+func reported(err error) error {
+	return &ReportedError{Err: err}
+}
+
 var Definitions = []*discordgo.ApplicationCommand{
 	HelpCommand,
 	PingCommand,
 	PlexRequestCommand,
+	PlexDiscoverCommand,
 	JellyLinkCommand,
 	PlexActivityCommand,
 	PlexFixMissingCommand,
 	GetRequestsCommand,
+	StatusCommand,
 }
 
 var Handlers = map[string]Handler{
 	HelpCommand.Name:           HelpHandler,
 	PingCommand.Name:           PingHandler,
 	PlexRequestCommand.Name:    PlexRequestHandler,
+	PlexDiscoverCommand.Name:   PlexDiscoverHandler,
 	JellyLinkCommand.Name:      JellyLinkHandler,
 	PlexActivityCommand.Name:   PlexActivityHandler,
 	PlexFixMissingCommand.Name: PlexFixMissingHandler,
 	GetRequestsCommand.Name:    GetRequestsHandler,
+	StatusCommand.Name:         StatusHandler,
+}
+
+// AutocompleteHandlers command name -> handler suggesting values for its focused option
+var AutocompleteHandlers = map[string]Handler{
+	PlexRequestCommand.Name:    PlexRequestAutocomplete,
+	PlexDiscoverCommand.Name:   PlexDiscoverAutocomplete,
+	PlexFixMissingCommand.Name: PlexFixMissingAutocomplete,
 }
 
-// ComponentHandlers CustomID -> handler
-var ComponentHandlers = map[string]ComponentHandler{
+// ComponentHandlers CustomID pattern -> handler; see ComponentRoutes.Match
+var ComponentHandlers = ComponentRoutes{
 	PlexRequestSelectID:         PlexRequestSelectHandler,
 	PlexRequestConfirmID:        PlexRequestConfirmHandler,
+	PlexRequestConfirm4KID:      PlexRequestConfirmHandler,
 	PlexRequestAbortID:          PlexRequestAbortHandler,
 	PlexRequestNotifyID:         PlexRequestNotifyHandler,
+	PlexRequestPrevID:           PlexRequestPageHandler,
+	PlexRequestNextID:           PlexRequestPageHandler,
+	PlexRequestSeasonsID:        PlexRequestSeasonsHandler,
+	PlexRequestSeasonsSendID:    PlexRequestSeasonsSendHandler,
+	PlexRequestAllSeasonsID:     PlexRequestSeasonsSendHandler,
+	PlexRequestAdvancedID:       PlexRequestAdvancedHandler,
+	PlexRequestServerID:         PlexRequestServerHandler,
+	PlexRequestProfileID:        PlexRequestAdvancedSelectHandler,
+	PlexRequestRootFolderID:     PlexRequestAdvancedSelectHandler,
+	PlexRequestLanguageID:       PlexRequestAdvancedSelectHandler,
+	PlexRequestAdvancedSendID:   PlexRequestAdvancedSendHandler,
+	PlexRequestBackID:           PlexRequestBackHandler,
+	PlexDiscoverPrevID:          PlexDiscoverPageHandler,
+	PlexDiscoverNextID:          PlexDiscoverPageHandler,
+	PlexDiscoverRequestID:       PlexDiscoverRequestHandler,
+	PlexDiscoverAbortID:         PlexDiscoverAbortHandler,
 	JellyLinkSelectID:           JellyLinkSelectHandler,
 	JellyLinkAbortID:            JellyLinkAbortHandler,
 	JellyLinkPrevID:             JellyLinkPrevHandler,
 	JellyLinkNextID:             JellyLinkNextHandler,
-	GetRequestsPrevID:           GetRequestsPrevHandler,
-	GetRequestsNextID:           GetRequestsNextHandler,
-	GetRequestsAbortID:          GetRequestsAbortHandler,
+	GetRequestsPageID:           GetRequestsPageHandler,
+	GetRequestsCloseID:          GetRequestsCloseHandler,
 	PlexFixMissingPageNext:      PlexFixMissingMediaPagingHandler,
 	PlexFixMissingPagePrev:      PlexFixMissingMediaPagingHandler,
 	PlexFixMissingSelectMedia:   PlexFixMissingMediaSelectHandler,
@@ -56,6 +114,62 @@ var ComponentHandlers = map[string]ComponentHandler{
 	PlexFixMissingAbort:         PlexFixMissingAbortHandler,
 }
 
+// ModalHandlers modal CustomID pattern -> handler for the submitted modal
+var ModalHandlers = ComponentRoutes{
+	PlexFixMissingOverrideModal: PlexFixMissingOverrideHandler,
+}
+
+// ComponentActions CustomID pattern (component or modal) -> permission
+// sub-action checked before the handler runs
+var ComponentActions = map[string]string{
+	PlexRequestConfirm4KID:      permissions.Request4K,
+	PlexRequestAdvancedID:       permissions.RequestAdvanced,
+	PlexRequestAdvancedSendID:   permissions.RequestAdvanced,
+	PlexFixMissingApprove:       permissions.FixMissingApprove,
+	PlexFixMissingOverrideModal: permissions.FixMissingApproveRejected,
+}
+
+// componentCommands CustomID pattern -> slash command, for patterns that
+// don't start with the command's name
+var componentCommands = map[string]string{
+	GetRequestsPageID:     GetRequestsCommand.Name,
+	GetRequestsCloseID:    GetRequestsCommand.Name,
+	PlexDiscoverRequestID: PlexRequestCommand.Name,
+}
+
+// SessionCounts returns, per interactive flow, a func reporting its live sessions.
+func SessionCounts() map[string]func() int {
+	return map[string]func() int{
+		PlexRequestCommand.Name:    func() int { return requestStore.Len() },
+		PlexDiscoverCommand.Name:   func() int { return discoverStore.Len() },
+		PlexFixMissingCommand.Name: func() int { return pfmStore.Len() },
+		JellyLinkCommand.Name:      func() int { return jellyLinkStore.Len() },
+	}
+}
+
+// Names returns the names of all slash commands.
+func Names() []string {
+	names := make([]string, 0, len(Definitions))
+	for _, d := range Definitions {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+// CommandForComponent returns the slash command a component pattern belongs
+// to, based on its prefix ("plex_request_select" -> "plex-request").
+func CommandForComponent(pattern string) string {
+	if cmd, ok := componentCommands[pattern]; ok {
+		return cmd
+	}
+	for _, d := range Definitions {
+		if strings.HasPrefix(pattern, strings.ReplaceAll(d.Name, "-", "_")+"_") {
+			return d.Name
+		}
+	}
+	return ""
+}
+
 func RegisterAll(s *discordgo.Session, guildID string) error {
 	appID := s.State.User.ID
 	_, err := s.ApplicationCommandBulkOverwrite(appID, guildID, Definitions)
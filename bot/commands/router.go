@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"strings"
+)
+
+// CustomIDs are colon-separated: a route pattern of one or more segments,
+// followed by the arguments the handler needs ("req:page:<owner>:2"). State
+// encoded this way survives restarts and works on messages the bot posted
+// outside any slash command, e.g. webhook notifications.
+const customIDSep = ":"
+
+// ComponentRoutes maps a CustomID pattern to its handler.
+type ComponentRoutes map[string]ComponentHandler
+
+// Match finds the route for customID. The longest registered pattern that
+// is a whole-segment prefix of customID wins.
+func (r ComponentRoutes) Match(customID string) (pattern string, h ComponentHandler, ok bool) {
+	segs := strings.Split(customID, customIDSep)
+	for n := len(segs); n > 0; n-- {
+		p := strings.Join(segs[:n], customIDSep)
+		if h, ok := r[p]; ok {
+			return p, h, true
+		}
+	}
+	return "", nil, false
+}
+
+// componentID builds a CustomID from a pattern and its arguments. Arguments
+// must not contain the separator; the result must stay within Discord's
+// 100 character limit.
+func componentID(pattern string, args ...string) string {
+	return strings.Join(append([]string{pattern}, args...), customIDSep)
+}
+
+// componentArgs returns the arguments encoded after pattern in customID.
+func componentArgs(customID, pattern string) []string {
+	rest, ok := strings.CutPrefix(customID, pattern+customIDSep)
+	if !ok || rest == "" {
+		return nil
+	}
+	return strings.Split(rest, customIDSep)
+}
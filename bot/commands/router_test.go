@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+)
+
+func noopComponent(*appctx.Context, *discordgo.Session, *discordgo.InteractionCreate) error {
+	return nil
+}
+
+func TestComponentRoutesMatch(t *testing.T) {
+	routes := ComponentRoutes{
+		"req":       noopComponent,
+		"req:page":  noopComponent,
+		"req:pagex": noopComponent,
+		"pfm_abort": noopComponent,
+	}
+
+	tests := []struct {
+		customID string
+		want     string // "" means no match
+	}{
+		{"req", "req"},
+		{"req:page", "req:page"},
+		{"req:page:123:2", "req:page"},
+		{"req:pagex:123", "req:pagex"},
+		{"req:pag:123", "req"},
+		{"req:paged", "req"},
+		{"req:", "req"},
+		{"pfm_abort:abc123", "pfm_abort"},
+		{"pfm_abortx:abc123", ""},
+		{"reqs:page", ""},
+		{"page", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.customID, func(t *testing.T) {
+			pattern, h, ok := routes.Match(tt.customID)
+			if ok != (tt.want != "") || pattern != tt.want {
+				t.Fatalf("Match(%q) = %q, %v; want %q", tt.customID, pattern, ok, tt.want)
+			}
+			if ok && h == nil {
+				t.Errorf("Match(%q) returned a nil handler", tt.customID)
+			}
+		})
+	}
+}
+
+func TestComponentArgs(t *testing.T) {
+	tests := []struct {
+		customID string
+		pattern  string
+		want     []string
+	}{
+		{componentID("req:page", "123", "2"), "req:page", []string{"123", "2"}},
+		{componentID("req:page"), "req:page", nil},
+		{"req:page:", "req:page", nil},
+		{"req:pagex:1", "req:page", nil},
+		{withSession("plex_request_select", "abc"), "plex_request_select", []string{"abc"}},
+	}
+
+	for _, tt := range tests {
+		if got := componentArgs(tt.customID, tt.pattern); !slices.Equal(got, tt.want) {
+			t.Errorf("componentArgs(%q, %q) = %q, want %q", tt.customID, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+// Every registered component must route to itself once a session ID is
+// appended, or a shorter pattern would steal its clicks.
+func TestComponentHandlersRouteToThemselves(t *testing.T) {
+	for _, routes := range []ComponentRoutes{ComponentHandlers, ModalHandlers} {
+		for pattern := range routes {
+			if got, _, ok := routes.Match(withSession(pattern, "abc123")); !ok || got != pattern {
+				t.Errorf("Match(%q + session) = %q, %v; want %q", pattern, got, ok, pattern)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/session"
+	"github.com/KevinHaeusler/go-haruki/bot/util"
+)
+
+// sessionEndedMessage answers clicks on a message whose session is gone
+// (finished, aborted, timed out or replaced).
+const sessionEndedMessage = "⌛ This session has ended. Run the command again to start a new one."
+
+// sessionOwner is implemented by every session type, so shared helpers can
+// tell who started a flow.
+type sessionOwner interface {
+	owner() string
+}
+
+// withSession scopes a component to one session by appending its ID to the
+// CustomID ("plex_request_select" -> "plex_request_select:<sid>").
+func withSession(customID, sid string) string {
+	return componentID(customID, sid)
+}
+
+// componentSessionID returns the session ID carried by the clicked component
+// or submitted modal.
+func componentSessionID(i *discordgo.InteractionCreate) string {
+	_, sid, _ := strings.Cut(interactionCustomID(i), customIDSep)
+	return sid
+}
+
+// notYourMenuMessage answers clicks on a flow someone else started.
+const notYourMenuMessage = "🚫 This isn't your menu. Run the command yourself to get your own."
+
+// openSession returns the session the clicked component belongs to and
+// touches it. It answers the click and returns nil when the session has
+// ended or the clicker is not its owner; the caller must not respond again.
+// Members allowed the override action may act on other users' sessions.
+func openSession[T sessionOwner](ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, store session.Store[T], override string) *T {
+	sid := componentSessionID(i)
+	var sess *T
+	if sid != "" {
+		sess = store.Get(sid)
+	}
+	if sess == nil {
+		_ = util.RespondEphemeral(s, i, sessionEndedMessage)
+		return nil
+	}
+	if !checkOwner(ctx, s, i, (*sess).owner(), override) {
+		return nil
+	}
+	store.Touch(sid)
+	return sess
+}
+
+// checkOwner reports whether the clicker may use a component owned by
+// ownerID: the owner always may, others only with the override action,
+// which is logged. Refused clicks are answered with notYourMenuMessage.
+func checkOwner(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, ownerID, override string) bool {
+	userID := util.InteractionUserID(i)
+	if userID == ownerID {
+		return true
+	}
+	if ctx.Perms.Can(i, override) {
+		ctx.Log.Warn("session owner override", "owner", ownerID, "action", override)
+		return true
+	}
+	_ = util.RespondEphemeral(s, i, notYourMenuMessage)
+	return false
+}
+
+// UseSessionDB moves every interactive flow onto db so sessions survive
+// restarts. Without it sessions live in memory. Call before WatchSessions.
+func UseSessionDB(db *session.DB) {
+	requestStore = session.New[requestSession](db, PlexRequestCommand.Name, PlexSessionTTL)
+	discoverStore = session.New[discoverSession](db, PlexDiscoverCommand.Name, plexDiscoverTTL)
+	pfmStore = session.New[pfmSession](db, PlexFixMissingCommand.Name, pfmSessionTTL)
+	jellyLinkStore = session.New[jellyLinkSession](db, JellyLinkCommand.Name, jellyLinkTTL)
+}
+
+// WatchSessions registers what each flow does to its message when a session
+// times out, and returns the stores for the janitor to sweep. Sessions that
+// expired while the bot was down are closed out on the first sweep.
+func WatchSessions(s *discordgo.Session) []session.Sweeper {
+	requestStore.OnExpire(func(_ string, d requestSession) {
+		_ = editSessionMessageSimple(s, d.ChannelID, d.MessageID, timedOutEmbed(requestStore.TTL()), []discordgo.MessageComponent{})
+	})
+	discoverStore.OnExpire(func(_ string, d discoverSession) {
+		_ = editSessionMessageSimple(s, d.ChannelID, d.MessageID, timedOutEmbed(discoverStore.TTL()), []discordgo.MessageComponent{})
+	})
+	pfmStore.OnExpire(func(_ string, d pfmSession) {
+		_ = editSessionMessageSimple(s, d.ChannelID, d.MessageID, timedOutEmbed(pfmStore.TTL()), []discordgo.MessageComponent{})
+	})
+	jellyLinkStore.OnExpire(func(_ string, d jellyLinkSession) {
+		_ = editSessionMessageSimple(s, d.ChannelID, d.MessageID, timedOutEmbed(jellyLinkStore.TTL()), []discordgo.MessageComponent{})
+	})
+
+	return []session.Sweeper{requestStore, discoverStore, pfmStore, jellyLinkStore}
+}
+
+func timedOutEmbed(ttl time.Duration) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Aborted",
+		Description: "Session timed out after " + idleTime(ttl) + " of inactivity.",
+		Color:       0xff0000,
+	}
+}
+
+// idleTime spells out a session TTL, e.g. "3 minutes" or "90 seconds".
+func idleTime(ttl time.Duration) string {
+	n, unit := int(ttl/time.Second), "second"
+	if ttl%time.Minute == 0 {
+		n, unit = int(ttl/time.Minute), "minute"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
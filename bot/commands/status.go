@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/health"
+	"github.com/KevinHaeusler/go-haruki/bot/ui"
+)
+
+// statusCheckTimeout bounds each service probe so one dead upstream can't
+// hold up the whole reply.
+const statusCheckTimeout = 8 * time.Second
+
+var StatusCommand = &discordgo.ApplicationCommand{
+	Name:        "status",
+	Description: "Show whether Jellyseerr, Sonarr, Radarr and Tautulli are up",
+}
+
+func StatusHandler(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Defer immediately (avoid Discord 3s timeout)
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		return err
+	}
+
+	results := health.Run(ctx.Context(), health.Upstreams(ctx), statusCheckTimeout)
+	for _, r := range results {
+		if r.Status != health.StatusOK {
+			ctx.Log.Info("status check", "service", r.Name, "status", r.Status, "error", r.Error, "warnings", len(r.Warnings))
+		}
+	}
+
+	embeds := []*discordgo.MessageEmbed{ui.StatusEmbed(results)}
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &embeds,
+	})
+	return err
+}
@@ -1,52 +1,230 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	JellyseerrURL    string
-	JellyseerrAPIKey string
-
-	RadarrURL    string
-	RadarrAPIKey string
+	Discord DiscordConfig `yaml:"discord"`
 
-	SonarrURL    string
-	SonarrAPIKey string
-
-	TautulliURL    string
-	TautulliAPIKey string
+	Jellyseerr ServiceConfig `yaml:"jellyseerr"`
+	Radarr     ServiceConfig `yaml:"radarr"`
+	Sonarr     ServiceConfig `yaml:"sonarr"`
+	Tautulli   ServiceConfig `yaml:"tautulli"`
 
 	// Optional webhook server to receive external notifications
-	WebhookAddr string // e.g. ":8080"
-	WebhookPath string // e.g. "/webhook"
+	Webhook WebhookConfig `yaml:"webhook"`
+
+	// Optional Prometheus endpoint
+	Metrics MetricsConfig `yaml:"metrics"`
 
 	// Optional: where to post incoming notifications
-	DiscordChannelID string
-
-	// Optional: authorization token for incoming webhooks
-	WebhookAuthToken string
-}
-
-func Load() (Config, error) {
-	c := Config{
-		JellyseerrURL:    os.Getenv("JELLYSEERR_URL"),
-		JellyseerrAPIKey: os.Getenv("JELLYSEERR_API_KEY"),
-		RadarrURL:        os.Getenv("RADARR_URL"),
-		RadarrAPIKey:     os.Getenv("RADARR_API_KEY"),
-		SonarrURL:        os.Getenv("SONARR_URL"),
-		SonarrAPIKey:     os.Getenv("SONARR_API_KEY"),
-		TautulliURL:      os.Getenv("TAUTULLI_URL"),
-		TautulliAPIKey:   os.Getenv("TAUTULLI_API_KEY"),
-		WebhookAddr:      os.Getenv("WEBHOOK_ADDR"),
-		WebhookPath:      os.Getenv("WEBHOOK_PATH"),
-		DiscordChannelID: os.Getenv("DISCORD_CHANNEL_ID"),
-		WebhookAuthToken: os.Getenv("WEBHOOK_AUTH_TOKEN"),
+	Channels ChannelConfig `yaml:"channels"`
+
+	Roles       RoleConfig        `yaml:"roles"`
+	Permissions PermissionsConfig `yaml:"permissions"`
+	Timeouts    TimeoutConfig     `yaml:"timeouts"`
+	HTTP        HTTPConfig        `yaml:"http"`
+	Cache       CacheConfig       `yaml:"cache"`
+	Sessions    SessionConfig     `yaml:"sessions"`
+	Limits      LimitsConfig      `yaml:"limits"`
+	Log         LogConfig         `yaml:"log"`
+}
+
+type DiscordConfig struct {
+	Token   string `yaml:"token"`
+	GuildID string `yaml:"guild_id"` // optional; commands are registered globally when empty
+}
+
+// ServiceConfig describes one upstream API (Jellyseerr, Radarr, ...).
+type ServiceConfig struct {
+	// Enabled is optional. When unset, a service counts as enabled as soon as
+	// its URL or API key is configured.
+	Enabled *bool  `yaml:"enabled"`
+	URL     string `yaml:"url"`
+	APIKey  string `yaml:"api_key"`
+}
+
+func (s ServiceConfig) IsEnabled() bool {
+	if s.Enabled != nil {
+		return *s.Enabled
+	}
+	return s.URL != "" || s.APIKey != ""
+}
+
+type WebhookConfig struct {
+	Addr      string `yaml:"addr"`       // e.g. ":8080"
+	Path      string `yaml:"path"`       // e.g. "/webhook"
+	AuthToken string `yaml:"auth_token"` // optional: required "Authorization" header value
+}
+
+// IsEnabled reports whether the HTTP server should be started at all.
+func (w WebhookConfig) IsEnabled() bool {
+	return w.Addr != ""
+}
+
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"` // own listener; empty serves metrics on the webhook server
+	Path    string `yaml:"path"`
+}
+
+type ChannelConfig struct {
+	// Notifications is the default channel for webhook notifications.
+	Notifications string `yaml:"notifications"`
+	// Events routes individual webhook events (e.g. MEDIA_AVAILABLE) to other channels.
+	Events map[string]string `yaml:"events"`
+}
+
+// ForEvent returns the channel a webhook event should be posted to.
+func (c ChannelConfig) ForEvent(event string) string {
+	key := strings.ToUpper(strings.TrimSpace(event))
+	for k, v := range c.Events {
+		if strings.ToUpper(strings.TrimSpace(k)) == key && v != "" {
+			return v
+		}
+	}
+	return c.Notifications
+}
+
+type RoleConfig struct {
+	// Plex is shorthand for a permissions rule on /plex-request and
+	// /plex-fix-missing; explicit permissions.commands entries win.
+	Plex string `yaml:"plex"`
+}
+
+type TimeoutConfig struct {
+	HTTP          Duration `yaml:"http"`           // one attempt of an upstream HTTP request
+	WebhookDedupe Duration `yaml:"webhook_dedupe"` // window for suppressing identical webhooks
+}
+
+// HTTPConfig tunes retries and per-upstream limits for Jellyseerr, Radarr,
+// Sonarr and Tautulli calls. Limits apply to each upstream host separately.
+type HTTPConfig struct {
+	Retries          int      `yaml:"retries"`           // extra attempts for transient failures
+	RetryBackoff     Duration `yaml:"retry_backoff"`     // first backoff, doubled per attempt
+	RetryMaxBackoff  Duration `yaml:"retry_max_backoff"` // cap for backoff and Retry-After
+	MaxConcurrent    int      `yaml:"max_concurrent"`    // requests in flight per upstream, 0 = unlimited
+	RateLimit        float64  `yaml:"rate_limit"`        // requests per second per upstream, 0 = unlimited
+	BreakerThreshold int      `yaml:"breaker_threshold"` // consecutive failures that open the circuit, 0 = off
+	BreakerCooldown  Duration `yaml:"breaker_cooldown"`  // how long an open circuit fails fast
+}
+
+type CacheConfig struct {
+	// UserIndexRefresh is how often the Discord <-> Jellyseerr user index is rebuilt.
+	UserIndexRefresh Duration `yaml:"user_index_refresh"`
+	// UserIndexFile optionally persists the user index across restarts.
+	UserIndexFile string `yaml:"user_index_file"`
+}
+
+type SessionConfig struct {
+	// File is a bbolt database that keeps interactive sessions across
+	// restarts. Empty keeps them in memory only.
+	File string `yaml:"file"`
+}
+
+// LimitsConfig throttles commands that are expensive upstream.
+type LimitsConfig struct {
+	// Cooldowns is how long a user waits between two runs of a slash
+	// command, keyed by command name.
+	Cooldowns map[string]CooldownRule `yaml:"cooldowns"`
+	// MaxSearches caps interactive release searches on Radarr/Sonarr running
+	// at once across all users, 0 = unlimited.
+	MaxSearches int `yaml:"max_searches"`
+}
+
+// CooldownRule is a per-user cooldown. Roles and Users override Default for
+// matching members; when several apply, the shortest wins. A zero or empty
+// duration means no cooldown.
+type CooldownRule struct {
+	Default Duration            `yaml:"default"`
+	Roles   map[string]Duration `yaml:"roles"`
+	Users   map[string]Duration `yaml:"users"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error
+	Format string `yaml:"format"` // text or json
+}
+
+// Duration is a time.Duration written as a Go duration string ("30s", "2m").
+// Parsing is deferred to Validate so every bad value is reported at once.
+type Duration struct {
+	time.Duration
+	raw string
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	d.raw = strings.TrimSpace(string(b))
+	return nil
+}
+
+func (d *Duration) set(raw string) {
+	d.raw = strings.TrimSpace(raw)
+}
+
+// Default returns the configuration used before the file and environment are applied.
+func Default() Config {
+	return Config{
+		Webhook: WebhookConfig{Path: "/webhook"},
+		Metrics: MetricsConfig{Path: "/metrics"},
+		Timeouts: TimeoutConfig{
+			HTTP:          Duration{raw: "15s"},
+			WebhookDedupe: Duration{raw: "30s"},
+		},
+		HTTP: HTTPConfig{
+			Retries:          2,
+			RetryBackoff:     Duration{raw: "500ms"},
+			RetryMaxBackoff:  Duration{raw: "5s"},
+			MaxConcurrent:    4,
+			BreakerThreshold: 5,
+			BreakerCooldown:  Duration{raw: "30s"},
+		},
+		Cache: CacheConfig{
+			UserIndexRefresh: Duration{raw: "10m"},
+		},
+		Limits: LimitsConfig{
+			Cooldowns: map[string]CooldownRule{
+				"plex-fix-missing": {Default: Duration{raw: "1m"}},
+			},
+			MaxSearches: 2,
+		},
+		Log: LogConfig{Level: "info", Format: "text"},
+	}
+}
+
+// Load builds the configuration from the optional YAML file at path, then
+// applies environment overrides and validates the result. Validation errors
+// list every problem found, not just the first.
+func Load(path string) (Config, error) {
+	c := Default()
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return c, fmt.Errorf("read config: %w", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+			return c, fmt.Errorf("parse config %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(&c); err != nil {
+		return c, err
 	}
 
-	if c.JellyseerrURL == "" {
-		return c, fmt.Errorf("missing JELLYSEERR_URL")
+	if err := c.Validate(); err != nil {
+		return c, err
 	}
 	return c, nil
 }
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envString maps an environment variable onto a string setting. Every
+// variable can also be given as NAME_FILE, pointing at a file that holds
+// the value (Docker/Kubernetes secrets).
+type envString struct {
+	name string
+	dst  *string
+}
+
+type envDuration struct {
+	name string
+	dst  *Duration
+}
+
+type envBool struct {
+	name string
+	dst  *bool
+}
+
+type envInt struct {
+	name string
+	dst  *int
+}
+
+type envFloat struct {
+	name string
+	dst  *float64
+}
+
+func applyEnv(c *Config) error {
+	strs := []envString{
+		{"BOT_TOKEN", &c.Discord.Token},
+		{"GUILD_ID", &c.Discord.GuildID},
+		{"JELLYSEERR_URL", &c.Jellyseerr.URL},
+		{"JELLYSEERR_API_KEY", &c.Jellyseerr.APIKey},
+		{"RADARR_URL", &c.Radarr.URL},
+		{"RADARR_API_KEY", &c.Radarr.APIKey},
+		{"SONARR_URL", &c.Sonarr.URL},
+		{"SONARR_API_KEY", &c.Sonarr.APIKey},
+		{"TAUTULLI_URL", &c.Tautulli.URL},
+		{"TAUTULLI_API_KEY", &c.Tautulli.APIKey},
+		{"WEBHOOK_ADDR", &c.Webhook.Addr},
+		{"WEBHOOK_PATH", &c.Webhook.Path},
+		{"WEBHOOK_AUTH_TOKEN", &c.Webhook.AuthToken},
+		{"METRICS_ADDR", &c.Metrics.Addr},
+		{"METRICS_PATH", &c.Metrics.Path},
+		{"DISCORD_CHANNEL_ID", &c.Channels.Notifications},
+		{"PLEX_ROLE_ID", &c.Roles.Plex},
+		{"USER_INDEX_FILE", &c.Cache.UserIndexFile},
+		{"SESSION_FILE", &c.Sessions.File},
+		{"LOG_LEVEL", &c.Log.Level},
+		{"LOG_FORMAT", &c.Log.Format},
+	}
+	durs := []envDuration{
+		{"HTTP_TIMEOUT", &c.Timeouts.HTTP},
+		{"WEBHOOK_DEDUPE_WINDOW", &c.Timeouts.WebhookDedupe},
+		{"HTTP_RETRY_BACKOFF", &c.HTTP.RetryBackoff},
+		{"HTTP_RETRY_MAX_BACKOFF", &c.HTTP.RetryMaxBackoff},
+		{"HTTP_BREAKER_COOLDOWN", &c.HTTP.BreakerCooldown},
+		{"USER_INDEX_REFRESH", &c.Cache.UserIndexRefresh},
+	}
+	bools := []envBool{
+		{"METRICS_ENABLED", &c.Metrics.Enabled},
+	}
+	ints := []envInt{
+		{"HTTP_RETRIES", &c.HTTP.Retries},
+		{"HTTP_MAX_CONCURRENT", &c.HTTP.MaxConcurrent},
+		{"HTTP_BREAKER_THRESHOLD", &c.HTTP.BreakerThreshold},
+		{"MAX_SEARCHES", &c.Limits.MaxSearches},
+	}
+	floats := []envFloat{
+		{"HTTP_RATE_LIMIT", &c.HTTP.RateLimit},
+	}
+
+	for _, e := range strs {
+		v, ok, err := lookupEnv(e.name)
+		if err != nil {
+			return err
+		}
+		if ok {
+			*e.dst = v
+		}
+	}
+	for _, e := range durs {
+		v, ok, err := lookupEnv(e.name)
+		if err != nil {
+			return err
+		}
+		if ok {
+			e.dst.set(v)
+		}
+	}
+	for _, e := range bools {
+		v, ok, err := lookupEnv(e.name)
+		if err != nil {
+			return err
+		}
+		if ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%s: not a boolean: %q", e.name, v)
+			}
+			*e.dst = b
+		}
+	}
+	for _, e := range ints {
+		v, ok, err := lookupEnv(e.name)
+		if err != nil {
+			return err
+		}
+		if ok {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%s: not a whole number: %q", e.name, v)
+			}
+			*e.dst = n
+		}
+	}
+	for _, e := range floats {
+		v, ok, err := lookupEnv(e.name)
+		if err != nil {
+			return err
+		}
+		if ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return fmt.Errorf("%s: not a number: %q", e.name, v)
+			}
+			*e.dst = f
+		}
+	}
+	return nil
+}
+
+// lookupEnv returns the value of name, or the trimmed contents of the file
+// named by name_FILE. Empty variables are treated as unset so a blank line in
+// .env does not wipe out a value from the config file.
+func lookupEnv(name string) (string, bool, error) {
+	if v := os.Getenv(name); v != "" {
+		return v, true, nil
+	}
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return "", false, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("read %s_FILE: %w", name, err)
+	}
+	return strings.TrimSpace(string(raw)), true, nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// PermissionsConfig declares who may run each slash command and each
+// sub-action (like grabbing a rejected release). Keys are command names
+// ("plex-request") and action names ("plex-fix-missing.approve").
+type PermissionsConfig struct {
+	Commands map[string]PermissionRule `yaml:"commands"`
+	Actions  map[string]PermissionRule `yaml:"actions"`
+}
+
+// PermissionRule allows a member matching any of its roles, user IDs or
+// Discord permissions. An empty rule allows everyone.
+type PermissionRule struct {
+	Roles       []string `yaml:"roles"`
+	Users       []string `yaml:"users"`
+	Permissions []string `yaml:"permissions"`
+}
+
+func (r PermissionRule) IsEmpty() bool {
+	return len(r.Roles) == 0 && len(r.Users) == 0 && len(r.Permissions) == 0
+}
+
+var discordPermissions = map[string]int64{
+	"administrator":    discordgo.PermissionAdministrator,
+	"manage_guild":     discordgo.PermissionManageGuild,
+	"manage_channels":  discordgo.PermissionManageChannels,
+	"manage_roles":     discordgo.PermissionManageRoles,
+	"manage_messages":  discordgo.PermissionManageMessages,
+	"manage_webhooks":  discordgo.PermissionManageWebhooks,
+	"moderate_members": discordgo.PermissionModerateMembers,
+	"kick_members":     discordgo.PermissionKickMembers,
+	"ban_members":      discordgo.PermissionBanMembers,
+	"view_audit_log":   discordgo.PermissionViewAuditLogs,
+}
+
+// PermissionBit returns the Discord permission flag for a config name like
+// "manage_guild".
+func PermissionBit(name string) (int64, bool) {
+	bit, ok := discordPermissions[strings.ToLower(strings.TrimSpace(name))]
+	return bit, ok
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ValidationError collects every problem found in a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid config: " + e.Problems[0]
+	}
+	return fmt.Sprintf("invalid config (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// reservedPaths are the health probes, served on the webhook server and the
+// metrics listener.
+var reservedPaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the configuration and parses durations in place. It
+// returns a *ValidationError listing every problem, or nil.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if c.Discord.Token == "" {
+		v.addf("discord.token is required (or set BOT_TOKEN)")
+	}
+	v.snowflake("discord.guild_id", c.Discord.GuildID)
+
+	// Jellyseerr powers most commands, so it is the one mandatory service.
+	if !c.Jellyseerr.IsEnabled() {
+		v.addf("jellyseerr.url is required (or set JELLYSEERR_URL)")
+	}
+	v.service("jellyseerr", c.Jellyseerr)
+	v.service("radarr", c.Radarr)
+	v.service("sonarr", c.Sonarr)
+	v.service("tautulli", c.Tautulli)
+
+	if c.Webhook.IsEnabled() {
+		if !strings.HasPrefix(c.Webhook.Path, "/") {
+			v.addf("webhook.path must start with \"/\", got %q", c.Webhook.Path)
+		}
+		if reservedPaths[c.Webhook.Path] {
+			v.addf("webhook.path %q is reserved for health checks", c.Webhook.Path)
+		}
+	}
+
+	if c.Metrics.Enabled {
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			v.addf("metrics.path must start with \"/\", got %q", c.Metrics.Path)
+		}
+		// the probes are served next to metrics on either listener
+		if reservedPaths[c.Metrics.Path] {
+			v.addf("metrics.path %q is reserved for health checks", c.Metrics.Path)
+		}
+		if c.Metrics.Addr == "" {
+			if !c.Webhook.IsEnabled() {
+				v.addf("metrics.addr is required when the webhook server is disabled")
+			} else if c.Metrics.Path == c.Webhook.Path {
+				v.addf("metrics.path must differ from webhook.path")
+			}
+		}
+	}
+
+	v.snowflake("channels.notifications", c.Channels.Notifications)
+	for event, ch := range c.Channels.Events {
+		v.snowflake("channels.events."+event, ch)
+	}
+	v.snowflake("roles.plex", c.Roles.Plex)
+	for name, rule := range c.Permissions.Commands {
+		v.permissionRule("permissions.commands."+name, rule)
+	}
+	for name, rule := range c.Permissions.Actions {
+		v.permissionRule("permissions.actions."+name, rule)
+	}
+
+	v.duration("timeouts.http", &c.Timeouts.HTTP)
+	v.duration("timeouts.webhook_dedupe", &c.Timeouts.WebhookDedupe)
+	v.duration("http.retry_backoff", &c.HTTP.RetryBackoff)
+	v.duration("http.retry_max_backoff", &c.HTTP.RetryMaxBackoff)
+	v.duration("http.breaker_cooldown", &c.HTTP.BreakerCooldown)
+	v.nonNegative("http.retries", float64(c.HTTP.Retries))
+	v.nonNegative("http.max_concurrent", float64(c.HTTP.MaxConcurrent))
+	v.nonNegative("http.rate_limit", c.HTTP.RateLimit)
+	v.nonNegative("http.breaker_threshold", float64(c.HTTP.BreakerThreshold))
+	v.duration("cache.user_index_refresh", &c.Cache.UserIndexRefresh)
+	for name, rule := range c.Limits.Cooldowns {
+		c.Limits.Cooldowns[name] = v.cooldownRule("limits.cooldowns."+name, rule)
+	}
+	v.nonNegative("limits.max_searches", float64(c.Limits.MaxSearches))
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		v.addf("log.level must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		v.addf("log.format must be text or json, got %q", c.Log.Format)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (v *validator) service(name string, s ServiceConfig) {
+	if !s.IsEnabled() {
+		return
+	}
+	if s.URL == "" {
+		v.addf("%s.url is required when %s is enabled", name, name)
+	} else if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.addf("%s.url must be an absolute http(s) URL, got %q", name, s.URL)
+	}
+	if s.APIKey == "" {
+		v.addf("%s.api_key is required when %s is enabled", name, name)
+	}
+}
+
+func (v *validator) snowflake(field, id string) {
+	if id == "" {
+		return
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			v.addf("%s must be a Discord ID (digits only), got %q", field, id)
+			return
+		}
+	}
+}
+
+func (v *validator) permissionRule(field string, r PermissionRule) {
+	for _, id := range r.Roles {
+		v.snowflake(field+".roles", id)
+	}
+	for _, id := range r.Users {
+		v.snowflake(field+".users", id)
+	}
+	for _, p := range r.Permissions {
+		if _, ok := PermissionBit(p); !ok {
+			v.addf("%s.permissions: unknown Discord permission %q", field, p)
+		}
+	}
+}
+
+func (v *validator) nonNegative(field string, n float64) {
+	if n < 0 {
+		v.addf("%s must not be negative, got %v", field, n)
+	}
+}
+
+func (v *validator) duration(field string, d *Duration) {
+	if d.raw == "" {
+		v.addf("%s is required", field)
+		return
+	}
+	parsed, err := time.ParseDuration(d.raw)
+	if err != nil {
+		v.addf("%s: cannot parse duration %q", field, d.raw)
+		return
+	}
+	if parsed <= 0 {
+		v.addf("%s must be positive, got %q", field, d.raw)
+		return
+	}
+	d.Duration = parsed
+}
+
+// cooldownRule parses the rule's durations and returns the parsed copy, since
+// map values can't be updated in place.
+func (v *validator) cooldownRule(field string, r CooldownRule) CooldownRule {
+	v.optionalDuration(field+".default", &r.Default)
+	roles := make(map[string]Duration, len(r.Roles))
+	for id, d := range r.Roles {
+		v.snowflake(field+".roles", id)
+		v.optionalDuration(field+".roles."+id, &d)
+		roles[id] = d
+	}
+	users := make(map[string]Duration, len(r.Users))
+	for id, d := range r.Users {
+		v.snowflake(field+".users", id)
+		v.optionalDuration(field+".users."+id, &d)
+		users[id] = d
+	}
+	r.Roles, r.Users = roles, users
+	return r
+}
+
+// optionalDuration is like duration, but empty and zero values are allowed.
+func (v *validator) optionalDuration(field string, d *Duration) {
+	if d.raw == "" {
+		d.Duration = 0
+		return
+	}
+	parsed, err := time.ParseDuration(d.raw)
+	if err != nil {
+		v.addf("%s: cannot parse duration %q", field, d.raw)
+		return
+	}
+	if parsed < 0 {
+		v.addf("%s must not be negative, got %q", field, d.raw)
+		return
+	}
+	d.Duration = parsed
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// validConfig returns the defaults plus the required settings.
+func validConfig() Config {
+	c := Default()
+	c.Discord.Token = "token"
+	c.Jellyseerr = ServiceConfig{URL: "http://jellyseerr:5055", APIKey: "key"}
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string // every problem, in order; nil means valid
+	}{
+		{
+			name:   "defaults with required settings",
+			modify: func(*Config) {},
+		},
+		{
+			name: "every problem is reported",
+			modify: func(c *Config) {
+				c.Discord.Token = ""
+				c.Discord.GuildID = "guild"
+				c.Radarr = ServiceConfig{URL: "radarr:7878"}
+				c.Timeouts.HTTP.set("soon")
+				c.HTTP.Retries = -1
+				c.Log.Level = "loud"
+			},
+			want: []string{
+				"discord.token is required (or set BOT_TOKEN)",
+				`discord.guild_id must be a Discord ID (digits only), got "guild"`,
+				`radarr.url must be an absolute http(s) URL, got "radarr:7878"`,
+				"radarr.api_key is required when radarr is enabled",
+				`timeouts.http: cannot parse duration "soon"`,
+				"http.retries must not be negative, got -1",
+				`log.level must be debug, info, warn or error, got "loud"`,
+			},
+		},
+		{
+			name:   "jellyseerr is required",
+			modify: func(c *Config) { c.Jellyseerr = ServiceConfig{} },
+			want:   []string{"jellyseerr.url is required (or set JELLYSEERR_URL)"},
+		},
+		{
+			name: "webhook path reserved for health checks",
+			modify: func(c *Config) {
+				c.Webhook.Addr = ":8080"
+				c.Webhook.Path = "/healthz"
+			},
+			want: []string{`webhook.path "/healthz" is reserved for health checks`},
+		},
+		{
+			name:   "metrics without a listener",
+			modify: func(c *Config) { c.Metrics.Enabled = true },
+			want:   []string{"metrics.addr is required when the webhook server is disabled"},
+		},
+		{
+			name: "metrics sharing the webhook path",
+			modify: func(c *Config) {
+				c.Webhook.Addr = ":8080"
+				c.Metrics.Enabled = true
+				c.Metrics.Path = "/webhook"
+			},
+			want: []string{"metrics.path must differ from webhook.path"},
+		},
+		{
+			name: "metrics path reserved on its own listener",
+			modify: func(c *Config) {
+				c.Metrics.Enabled = true
+				c.Metrics.Addr = ":9090"
+				c.Metrics.Path = "/readyz"
+			},
+			want: []string{`metrics.path "/readyz" is reserved for health checks`},
+		},
+		{
+			name: "metrics path reserved on the webhook server",
+			modify: func(c *Config) {
+				c.Webhook.Addr = ":8080"
+				c.Metrics.Enabled = true
+				c.Metrics.Path = "/healthz"
+			},
+			want: []string{`metrics.path "/healthz" is reserved for health checks`},
+		},
+		{
+			name: "durations must be positive",
+			modify: func(c *Config) {
+				c.Timeouts.WebhookDedupe.set("0s")
+				c.Cache.UserIndexRefresh.set("")
+			},
+			want: []string{
+				`timeouts.webhook_dedupe must be positive, got "0s"`,
+				"cache.user_index_refresh is required",
+			},
+		},
+		{
+			name: "cooldowns allow zero but not negative",
+			modify: func(c *Config) {
+				c.Limits.Cooldowns = map[string]CooldownRule{
+					"plex-request": {
+						Default: Duration{raw: "0s"},
+						Users:   map[string]Duration{"123": {raw: "-1m"}},
+					},
+				}
+			},
+			want: []string{`limits.cooldowns.plex-request.users.123 must not be negative, got "-1m"`},
+		},
+		{
+			name: "unknown permission name",
+			modify: func(c *Config) {
+				c.Permissions.Actions = map[string]PermissionRule{
+					"session.use-any": {Permissions: []string{"be_cool"}},
+				}
+			},
+			want: []string{`permissions.actions.session.use-any.permissions: unknown Discord permission "be_cool"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(&c)
+
+			err := c.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() = %v, want *ValidationError", err)
+			}
+			if !slices.Equal(verr.Problems, tt.want) {
+				t.Errorf("problems:\n got %q\nwant %q", verr.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateParsesDurations(t *testing.T) {
+	c := validConfig()
+	c.Timeouts.HTTP.set("90s")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if c.Timeouts.HTTP.Duration != 90*time.Second {
+		t.Errorf("timeouts.http = %v, want 1m30s", c.Timeouts.HTTP.Duration)
+	}
+	if got := c.Limits.Cooldowns["plex-fix-missing"].Default.Duration; got != time.Minute {
+		t.Errorf("plex-fix-missing cooldown = %v, want 1m", got)
+	}
+}
@@ -1,43 +1,92 @@
 package handlers
 
 import (
-	"log"
-
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/KevinHaeusler/go-haruki/bot/appctx"
 	"github.com/KevinHaeusler/go-haruki/bot/commands"
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
+	"github.com/KevinHaeusler/go-haruki/bot/metrics"
+	"github.com/KevinHaeusler/go-haruki/bot/util"
 )
 
-func NewInteractionHandler(ctx *appctx.Context) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+// route is what an interaction resolved to.
+type route struct {
+	kind    string // command, autocomplete, component or modal
+	id      string // command name or raw CustomID, for logs
+	name    string // command name or CustomID pattern, for metrics
+	handler commands.Handler
+	command string // slash command whose permission rule applies
+	action  string // optional permission sub-action
+	limit   string // slash command whose cooldown applies
+}
+
+func NewInteractionHandler(base *appctx.Context) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		switch i.Type {
-
-		case discordgo.InteractionApplicationCommand:
-			name := i.ApplicationCommandData().Name
-			h, ok := commands.Handlers[name]
-			if !ok {
-				log.Println("No slash handler for:", name)
-				return
-			}
-			if err := h(ctx, s, i); err != nil {
-				log.Println("Slash handler error:", name, err)
-			}
-
-		case discordgo.InteractionMessageComponent:
-			cd := i.MessageComponentData()
-			customID := cd.CustomID
-			h, ok := commands.ComponentHandlers[customID]
-			if !ok {
-				log.Println("No component handler for:", customID)
-				return
-			}
-			if err := h(ctx, s, i); err != nil {
-				log.Println("Component handler error:", customID, err)
-			}
-
-		default:
-			// ignore
+		r, ok := resolve(i)
+		if r.kind == "" {
+			return // ignore
 		}
+		ctx := base.WithCorrelation(logging.NewID(), "user", util.InteractionUserID(i), r.kind, r.id)
+		if !ok {
+			ctx.Log.Warn("no " + r.kind + " handler")
+			metrics.Interaction(r.kind, r.name, metrics.OutcomeUnhandled, 0)
+			return
+		}
+
+		_ = r.chain()(ctx, s, i)
+	}
+}
+
+// chain wraps the route's handler in the middlewares every interaction runs
+// through.
+func (r route) chain() commands.Handler {
+	return Chain(r.handler,
+		Observe(r.kind, r.name),
+		ReportErrors(),
+		Recover(),
+		RequireCommand(r.command),
+		RequireAction(r.action),
+		Cooldown(r.limit),
+	)
+}
+
+// resolve finds the handler for an interaction. ok is false when the kind is
+// known but nothing is registered for it.
+func resolve(i *discordgo.InteractionCreate) (r route, ok bool) {
+	switch i.Type {
+
+	case discordgo.InteractionApplicationCommand:
+		name := i.ApplicationCommandData().Name
+		h, ok := commands.Handlers[name]
+		return route{kind: "command", id: name, name: name, handler: h, command: name, limit: name}, ok
+
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		name := i.ApplicationCommandData().Name
+		h, ok := commands.AutocompleteHandlers[name]
+		return route{kind: "autocomplete", id: name, name: name, handler: h, command: name}, ok
+
+	case discordgo.InteractionMessageComponent:
+		return resolveCustomID("component", i.MessageComponentData().CustomID, commands.ComponentHandlers)
+
+	case discordgo.InteractionModalSubmit:
+		return resolveCustomID("modal", i.ModalSubmitData().CustomID, commands.ModalHandlers)
+	}
+	return route{}, false
+}
+
+func resolveCustomID(kind, customID string, routes commands.ComponentRoutes) (route, bool) {
+	pattern, h, ok := routes.Match(customID)
+	if !ok {
+		// unknown IDs are user-controlled, so don't use them as a label
+		return route{kind: kind, id: customID, name: "unknown"}, false
 	}
+	return route{
+		kind:    kind,
+		id:      customID,
+		name:    pattern,
+		handler: commands.Handler(h),
+		command: commands.CommandForComponent(pattern),
+		action:  commands.ComponentActions[pattern],
+	}, true
 }
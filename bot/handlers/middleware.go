@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/commands"
+	"github.com/KevinHaeusler/go-haruki/bot/limits"
+	"github.com/KevinHaeusler/go-haruki/bot/metrics"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
+	"github.com/KevinHaeusler/go-haruki/bot/util"
+)
+
+// Middleware wraps a handler with behaviour shared by every interaction.
+type Middleware func(next commands.Handler) commands.Handler
+
+// Chain wraps h so that the first middleware is the outermost.
+func Chain(h commands.Handler, mws ...Middleware) commands.Handler {
+	for n := len(mws) - 1; n >= 0; n-- {
+		h = mws[n](h)
+	}
+	return h
+}
+
+// errDenied is returned by the permission middlewares after the user has
+// been told, so the outer ones count a denial rather than a failure.
+var errDenied = errors.New("permission denied")
+
+// errThrottled is returned by Cooldown after the user has been told when
+// they can try again.
+var errThrottled = errors.New("on cooldown")
+
+// errorMessage is the reply to a failed interaction; the reference is the
+// correlation ID, so a report can be matched to the logs.
+const errorMessage = "⚠️ Something went wrong (ref: %s). Please try again later."
+
+// Observe times the handler, logs the result and records it in the
+// interaction metrics under kind and name.
+func Observe(kind, name string) Middleware {
+	return func(next commands.Handler) commands.Handler {
+		return func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			start := time.Now()
+			err := next(ctx, s, i)
+			d := time.Since(start)
+			switch {
+			case errors.Is(err, errDenied):
+				metrics.Interaction(kind, name, metrics.OutcomeDenied, 0)
+			case errors.Is(err, errThrottled):
+				metrics.Interaction(kind, name, metrics.OutcomeThrottled, 0)
+			case err != nil:
+				ctx.Log.Error(kind+" handler failed", "err", err, "duration", d)
+				metrics.Interaction(kind, name, metrics.OutcomeError, d)
+			default:
+				ctx.Log.Debug(kind+" handler done", "duration", d)
+				metrics.Interaction(kind, name, metrics.OutcomeOK, d)
+			}
+			return err
+		}
+	}
+}
+
+// ReportErrors tells the user when the handler fails, quoting the
+// correlation ID. The reply is an ephemeral response, or a follow-up if the
+// handler already acknowledged the interaction. A *commands.ReportedError
+// has already been shown, so it is passed on without a reply.
+func ReportErrors() Middleware {
+	return func(next commands.Handler) commands.Handler {
+		return func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			err := next(ctx, s, i)
+			var shown *commands.ReportedError
+			if err == nil || errors.Is(err, errDenied) || errors.Is(err, errThrottled) || errors.As(err, &shown) {
+				return err
+			}
+			if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+				_ = respondNoChoices(s, i)
+				return err
+			}
+			msg := fmt.Sprintf(errorMessage, ctx.CorrelationID())
+			if rerr := util.RespondEphemeral(s, i, msg); rerr != nil {
+				if _, ferr := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+					Content: msg,
+					Flags:   discordgo.MessageFlagsEphemeral,
+				}); ferr != nil {
+					ctx.Log.Warn("reporting error to user failed", "err", ferr)
+				}
+			}
+			return err
+		}
+	}
+}
+
+// Recover turns a panic in the handler into an error, so one bad handler
+// can't take down the gateway goroutine.
+func Recover() Middleware {
+	return func(next commands.Handler) commands.Handler {
+		return func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) (err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					ctx.Log.Error("handler panicked", "panic", v, "stack", string(debug.Stack()))
+					err = fmt.Errorf("panic: %v", v)
+				}
+			}()
+			return next(ctx, s, i)
+		}
+	}
+}
+
+// RequireCommand lets the interaction through only if the user may use the
+// slash command. An empty command allows everyone.
+func RequireCommand(command string) Middleware {
+	return func(next commands.Handler) commands.Handler {
+		return func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			if command != "" && !ctx.Perms.CanUseCommand(i, command) {
+				deny(ctx, s, i, fmt.Sprintf("use `/%s`", command))
+				return errDenied
+			}
+			return next(ctx, s, i)
+		}
+	}
+}
+
+// RequireAction lets the interaction through only if the user may perform
+// the permission sub-action. An empty action allows everyone.
+func RequireAction(action string) Middleware {
+	return func(next commands.Handler) commands.Handler {
+		return func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			if action != "" && !ctx.Perms.Can(i, action) {
+				deny(ctx, s, i, "do that")
+				return errDenied
+			}
+			return next(ctx, s, i)
+		}
+	}
+}
+
+// Cooldown lets the user run the slash command only once per configured
+// cooldown. A run that fails or panics, or that returns commands.ErrNotRun
+// after telling the user why, doesn't count. An empty command, or one
+// without a cooldown, is never throttled.
+func Cooldown(command string) Middleware {
+	return func(next commands.Handler) commands.Handler {
+		return func(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			if command == "" || ctx.Limits == nil {
+				return notRunIsNil(next(ctx, s, i))
+			}
+			if until, ok := ctx.Limits.Begin(i, command); !ok {
+				ctx.Log.Info("command on cooldown", "until", until)
+				_ = util.RespondEphemeral(s, i, fmt.Sprintf(limits.CooldownMessage, command, until.Unix()))
+				return errThrottled
+			}
+			succeeded := false
+			defer func() {
+				// deferred, so a panic on its way to Recover gives it back too
+				if !succeeded {
+					ctx.Limits.Cancel(i, command)
+				}
+			}()
+			err := next(ctx, s, i)
+			succeeded = err == nil
+			return notRunIsNil(err)
+		}
+	}
+}
+
+// notRunIsNil drops commands.ErrNotRun: the user already knows and there is
+// nothing to report.
+func notRunIsNil(err error) error {
+	if errors.Is(err, commands.ErrNotRun) {
+		return nil
+	}
+	return err
+}
+
+func deny(ctx *appctx.Context, s *discordgo.Session, i *discordgo.InteractionCreate, what string) {
+	ctx.Log.Info("permission denied", "what", what)
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		// no room for a message here; just suggest nothing
+		_ = respondNoChoices(s, i)
+		return
+	}
+	_ = util.RespondEphemeral(s, i, fmt.Sprintf(permissions.DeniedMessage, what))
+}
+
+func respondNoChoices(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: []*discordgo.ApplicationCommandOptionChoice{}},
+	})
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/commands"
+	"github.com/KevinHaeusler/go-haruki/bot/config"
+	"github.com/KevinHaeusler/go-haruki/bot/limits"
+	"github.com/KevinHaeusler/go-haruki/bot/metrics"
+	"github.com/KevinHaeusler/go-haruki/bot/permissions"
+)
+
+const testCommand = "plex-fix-missing"
+
+// fakeDiscord answers every REST call with 204 and keeps the request bodies,
+// so tests can see what the bot told the user.
+type fakeDiscord struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (f *fakeDiscord) RoundTrip(r *http.Request) (*http.Response, error) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+	}
+	f.mu.Lock()
+	f.bodies = append(f.bodies, string(body))
+	f.mu.Unlock()
+	return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Header: http.Header{}, Request: r}, nil
+}
+
+func (f *fakeDiscord) said(text string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, b := range f.bodies {
+		if strings.Contains(b, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func testSetup(t *testing.T) (*appctx.Context, *discordgo.Session, *fakeDiscord) {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Limits.Cooldowns = map[string]config.CooldownRule{
+		testCommand: {Default: config.Duration{Duration: time.Minute}},
+	}
+	perms, err := permissions.New(cfg, []string{testCommand})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lim, err := limits.New(cfg.Limits, []string{testCommand})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeDiscord{}
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Client = &http.Client{Transport: fake}
+
+	base := &appctx.Context{Config: cfg, Perms: perms, Limits: lim}
+	return base.WithCorrelation("test"), s, fake
+}
+
+func slashInteraction() *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:     "1",
+		Token:  "token",
+		Type:   discordgo.InteractionApplicationCommand,
+		Member: &discordgo.Member{User: &discordgo.User{ID: "42"}},
+		Data:   discordgo.ApplicationCommandInteractionData{Name: testCommand},
+	}}
+}
+
+func TestCooldown(t *testing.T) {
+	tests := []struct {
+		name      string
+		firstRun  func() error
+		throttled bool // second run is refused
+	}{
+		{"success counts", func() error { return nil }, true},
+		{"error doesn't count", func() error { return io.ErrUnexpectedEOF }, false},
+		{"ErrNotRun doesn't count", func() error { return commands.ErrNotRun }, false},
+		{"reported error doesn't count", func() error { return &commands.ReportedError{Err: io.ErrUnexpectedEOF} }, false},
+		{"panic doesn't count", func() error { panic("boom") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, s, fake := testSetup(t)
+			runs := 0
+			r := route{
+				kind: "command", id: testCommand, name: testCommand,
+				command: testCommand, limit: testCommand,
+				handler: func(*appctx.Context, *discordgo.Session, *discordgo.InteractionCreate) error {
+					runs++
+					if runs == 1 {
+						return tt.firstRun()
+					}
+					return nil
+				},
+			}
+			h := r.chain()
+
+			_ = h(ctx, s, slashInteraction())
+			_ = h(ctx, s, slashInteraction())
+
+			wantRuns := 2
+			if tt.throttled {
+				wantRuns = 1
+			}
+			if runs != wantRuns {
+				t.Errorf("handler ran %d times, want %d", runs, wantRuns)
+			}
+			if got := fake.said("You can use"); got != tt.throttled {
+				t.Errorf("cooldown message sent = %v, want %v", got, tt.throttled)
+			}
+		})
+	}
+}
+
+// interactionCount reads haruki_interactions_total for one command and
+// outcome from the metrics endpoint.
+func interactionCount(t *testing.T, name, outcome string) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	prefix := fmt.Sprintf(`haruki_interactions_total{kind="command",name=%q,outcome=%q} `, name, outcome)
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if v, ok := strings.CutPrefix(line, prefix); ok {
+			return v
+		}
+	}
+	return "0"
+}
+
+func TestOutcomes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		outcome  string
+		reported bool // user gets the generic error reply
+	}{
+		{"ok", nil, metrics.OutcomeOK, false},
+		{"refusal", commands.ErrNotRun, metrics.OutcomeOK, false},
+		{"failure", io.ErrUnexpectedEOF, metrics.OutcomeError, true},
+		{"upstream failure already shown", &commands.ReportedError{Err: io.ErrUnexpectedEOF}, metrics.OutcomeError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, s, fake := testSetup(t)
+			name := "outcome-" + tt.name
+			r := route{
+				kind: "command", id: name, name: name, command: testCommand, limit: testCommand,
+				handler: func(*appctx.Context, *discordgo.Session, *discordgo.InteractionCreate) error {
+					return tt.err
+				},
+			}
+
+			_ = r.chain()(ctx, s, slashInteraction())
+
+			if got := interactionCount(t, name, tt.outcome); got != "1" {
+				t.Errorf("interactions{outcome=%q} = %s, want 1", tt.outcome, got)
+			}
+			if got := fake.said("Something went wrong"); got != tt.reported {
+				t.Errorf("error reply sent = %v, want %v", got, tt.reported)
+			}
+		})
+	}
+}
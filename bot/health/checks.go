@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/appctx"
+	"github.com/KevinHaeusler/go-haruki/bot/clients/radarr"
+	"github.com/KevinHaeusler/go-haruki/bot/clients/sonarr"
+)
+
+// Upstreams returns a check for every configured upstream service. The Arr
+// checks also collect the warnings shown under System > Status.
+func Upstreams(ctx *appctx.Context) []Check {
+	var checks []Check
+	if ctx.Jelly != nil {
+		checks = append(checks, Check{Name: "jellyseerr", Probe: func(c context.Context) (Info, error) {
+			st, err := ctx.Jelly.GetStatus(c)
+			if err != nil {
+				return Info{}, err
+			}
+			return Info{Version: st.Version}, nil
+		}})
+	}
+	if ctx.Radarr != nil {
+		checks = append(checks, Check{Name: "radarr", Probe: func(c context.Context) (Info, error) {
+			st, err := ctx.Radarr.GetSystemStatus(c)
+			if err != nil {
+				return Info{}, err
+			}
+			info := Info{Version: st.Version}
+			hc, err := ctx.Radarr.GetHealth(c)
+			if err != nil {
+				info.Warnings = append(info.Warnings, "health check unavailable: "+err.Error())
+			}
+			for _, h := range hc {
+				info.Warnings = appendArrWarning(info.Warnings, radarrWarning(h))
+			}
+			return info, nil
+		}})
+	}
+	if ctx.Sonarr != nil {
+		checks = append(checks, Check{Name: "sonarr", Probe: func(c context.Context) (Info, error) {
+			st, err := ctx.Sonarr.GetSystemStatus(c)
+			if err != nil {
+				return Info{}, err
+			}
+			info := Info{Version: st.Version}
+			hc, err := ctx.Sonarr.GetHealth(c)
+			if err != nil {
+				info.Warnings = append(info.Warnings, "health check unavailable: "+err.Error())
+			}
+			for _, h := range hc {
+				info.Warnings = appendArrWarning(info.Warnings, sonarrWarning(h))
+			}
+			return info, nil
+		}})
+	}
+	if ctx.Tautulli != nil {
+		checks = append(checks, Check{Name: "tautulli", Probe: func(c context.Context) (Info, error) {
+			srv, err := ctx.Tautulli.GetServerInfo(c)
+			if err != nil {
+				return Info{}, err
+			}
+			info := Info{Version: srv.PMSVersion}
+			if srv.PMSName != "" {
+				info.Version = fmt.Sprintf("%s (Plex %s)", srv.PMSName, srv.PMSVersion)
+			}
+			return info, nil
+		}})
+	}
+	return checks
+}
+
+type arrWarning struct {
+	level, message string
+}
+
+func radarrWarning(h radarr.HealthCheck) arrWarning { return arrWarning{h.Type, h.Message} }
+func sonarrWarning(h sonarr.HealthCheck) arrWarning { return arrWarning{h.Type, h.Message} }
+
+// appendArrWarning keeps warnings and errors; notices are informational only.
+func appendArrWarning(list []string, w arrWarning) []string {
+	switch w.level {
+	case "warning", "error":
+		return append(list, fmt.Sprintf("%s: %s", w.level, w.message))
+	}
+	return list
+}
+
+// Gateway checks that the Discord gateway connection is up. session returns
+// the current session, which is nil before startup and after shutdown.
+func Gateway(session func() *discordgo.Session) Check {
+	return Check{Name: "discord", Probe: func(context.Context) (Info, error) {
+		s := session()
+		if s == nil {
+			return Info{}, errors.New("no session")
+		}
+		s.RLock()
+		ready := s.DataReady
+		s.RUnlock()
+		if !ready {
+			return Info{}, errors.New("gateway not connected")
+		}
+		return Info{Version: "api v" + discordgo.APIVersion}, nil
+	}}
+}
@@ -0,0 +1,102 @@
+// Package health checks the bot's dependencies and serves /healthz and /readyz.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Dependency states.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // reachable, but reporting problems
+	StatusDown     = "down"
+)
+
+// Info is what a successful probe learned about a dependency.
+type Info struct {
+	Version  string
+	Warnings []string
+}
+
+// Check probes one dependency.
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) (Info, error)
+}
+
+// Result is the outcome of one Check.
+type Result struct {
+	Name      string        `json:"-"`
+	OK        bool          `json:"-"` // reachable; may still be degraded
+	Status    string        `json:"status"`
+	Version   string        `json:"version,omitempty"`
+	Warnings  []string      `json:"warnings,omitempty"`
+	Latency   time.Duration `json:"-"`
+	LatencyMS int64         `json:"latency_ms"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Report is the JSON body of /healthz and /readyz.
+type Report struct {
+	Status       string            `json:"status"`
+	Dependencies map[string]Result `json:"dependencies"`
+}
+
+// Run executes all checks concurrently, each bounded by timeout, and returns
+// the results sorted by name.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) []Result {
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for idx, c := range checks {
+		wg.Add(1)
+		go func(idx int, c Check) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			info, err := c.Probe(cctx)
+			r := Result{Name: c.Name, OK: err == nil, Status: StatusOK, Version: info.Version, Warnings: info.Warnings, Latency: time.Since(start)}
+			r.LatencyMS = r.Latency.Milliseconds()
+			switch {
+			case err != nil:
+				r.Status = StatusDown
+				r.Error = err.Error()
+			case len(info.Warnings) > 0:
+				r.Status = StatusDegraded
+			}
+			results[idx] = r
+		}(idx, c)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(a, b int) bool { return results[a].Name < results[b].Name })
+	return results
+}
+
+// Handler serves a Report for checks, with 503 if any dependency is down.
+func Handler(checks func() []Check, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := Run(r.Context(), checks(), timeout)
+
+		rep := Report{Status: StatusOK, Dependencies: make(map[string]Result, len(results))}
+		code := http.StatusOK
+		for _, res := range results {
+			rep.Dependencies[res.Name] = res
+			if !res.OK {
+				rep.Status = StatusDown
+				code = http.StatusServiceUnavailable
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(rep)
+	})
+}
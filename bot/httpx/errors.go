@@ -0,0 +1,48 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the upstream while its
+// circuit breaker is open after repeated failures.
+var ErrCircuitOpen = errors.New("upstream temporarily unavailable (circuit open)")
+
+// StatusError is returned for any non-2xx response.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// Body is the start of the response body, trimmed so it can be shown in Discord.
+	Body string
+	// RetryAfter is the server's Retry-After hint, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("http %d: %s", e.StatusCode, e.Body)
+}
+
+// Temporary reports whether retrying the request later may succeed.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// IsStatus reports whether err is a *StatusError with the given status code.
+func IsStatus(err error, code int) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == code
+}
+
+// IsTimeout reports whether err is a deadline or network timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
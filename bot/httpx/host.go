@@ -0,0 +1,130 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// host tracks concurrency, rate limiting and the circuit breaker for one upstream.
+type host struct {
+	name    string
+	service string // metrics label, the host name unless set via Client.Name
+	opts    *Options
+
+	sem chan struct{} // nil when concurrency is unlimited
+
+	mu        sync.Mutex
+	nextSlot  time.Time // rate limiter: earliest start of the next request
+	failures  int       // consecutive failures
+	openUntil time.Time
+	probing   bool // half-open: one trial request is in flight
+}
+
+func newHost(name string, opts *Options) *host {
+	h := &host{name: name, service: name, opts: opts}
+	if opts.MaxConcurrent > 0 {
+		h.sem = make(chan struct{}, opts.MaxConcurrent)
+	}
+	return h
+}
+
+// acquire waits for a concurrency slot and a rate-limit token. The returned
+// release func must be called once the response has been read.
+func (h *host) acquire(ctx context.Context) (func(), error) {
+	if err := h.allow(); err != nil {
+		return nil, err
+	}
+
+	release := func() {}
+	if h.sem != nil {
+		select {
+		case h.sem <- struct{}{}:
+			release = func() { <-h.sem }
+		case <-ctx.Done():
+			h.cancelProbe()
+			return nil, ctx.Err()
+		}
+	}
+
+	if h.opts.RateLimit > 0 {
+		interval := time.Duration(float64(time.Second) / h.opts.RateLimit)
+		h.mu.Lock()
+		now := time.Now()
+		slot := h.nextSlot
+		if slot.Before(now) {
+			slot = now
+		}
+		h.nextSlot = slot.Add(interval)
+		h.mu.Unlock()
+
+		if err := sleep(ctx, slot.Sub(now)); err != nil {
+			release()
+			h.cancelProbe()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// allow checks the circuit breaker. After the cooldown a single trial
+// request is let through; its outcome closes or re-opens the circuit.
+func (h *host) allow() error {
+	if h.opts.BreakerThreshold <= 0 {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failures < h.opts.BreakerThreshold {
+		return nil
+	}
+	if time.Now().Before(h.openUntil) || h.probing {
+		return fmt.Errorf("%s: %w", h.name, ErrCircuitOpen)
+	}
+	h.probing = true
+	return nil
+}
+
+func (h *host) cancelProbe() {
+	h.mu.Lock()
+	h.probing = false
+	h.mu.Unlock()
+}
+
+// record feeds the outcome of a request into the circuit breaker.
+func (h *host) record(ok bool) {
+	if h.opts.BreakerThreshold <= 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.probing = false
+	if ok {
+		if h.failures >= h.opts.BreakerThreshold {
+			slog.Info("upstream recovered, closing circuit", "host", h.name)
+		}
+		h.failures = 0
+		return
+	}
+	h.failures++
+	if h.failures >= h.opts.BreakerThreshold {
+		h.openUntil = time.Now().Add(h.opts.BreakerCooldown)
+		slog.Warn("upstream failing, opening circuit", "host", h.name, "failures", h.failures, "cooldown", h.opts.BreakerCooldown)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
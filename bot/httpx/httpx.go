@@ -4,51 +4,177 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
+	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
+	"github.com/KevinHaeusler/go-haruki/bot/metrics"
 )
 
+// Options tunes timeouts, retries and per-upstream limits. Zero values
+// disable the corresponding feature, except Timeout which defaults to 15s.
+type Options struct {
+	// Timeout bounds a single attempt, not the whole call.
+	Timeout time.Duration
+
+	// Retries is how many times a failed idempotent request (or any request
+	// answered with 429) is retried.
+	Retries         int
+	RetryBackoff    time.Duration // first backoff, doubled per attempt
+	RetryMaxBackoff time.Duration // cap for backoff and Retry-After
+
+	// Limits applied per upstream host.
+	MaxConcurrent int     // requests in flight
+	RateLimit     float64 // requests per second
+
+	// BreakerThreshold consecutive failures (timeouts, connection errors,
+	// 5xx) open the circuit for BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
 type Client struct {
 	HTTP *http.Client
+
+	opts  Options
+	mu    sync.Mutex
+	hosts map[string]*host
 }
 
-func New() *Client {
+func New(opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 15 * time.Second
+	}
 	return &Client{
-		HTTP: &http.Client{Timeout: 60 * time.Second},
+		HTTP:  &http.Client{Timeout: opts.Timeout},
+		opts:  opts,
+		hosts: make(map[string]*host),
+	}
+}
+
+// Name labels requests to rawURL's host as service (e.g. "sonarr") in metrics.
+func (c *Client) Name(rawURL, service string) {
+	if rawURL == "" {
+		return
+	}
+	h := c.host(rawURL)
+	c.mu.Lock()
+	h.service = service
+	c.mu.Unlock()
+}
+
+func (c *Client) host(rawURL string) *host {
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		name = u.Host
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.hosts[name]
+	if !ok {
+		h = newHost(name, &c.opts)
+		c.hosts[name] = h
 	}
+	return h
 }
 
 func (c *Client) DoJSON(ctx context.Context, method, url string, headers map[string]string, reqBody any, out any) error {
-	var body io.Reader
+	var payload []byte
 	if reqBody != nil {
 		b, err := json.Marshal(reqBody)
 		if err != nil {
 			return err
 		}
-		body = bytes.NewReader(b)
+		payload = b
+	}
+
+	h := c.host(url)
+	for attempt := 0; ; attempt++ {
+		raw, err := c.do(ctx, h, method, url, headers, payload)
+		if err == nil {
+			if out == nil {
+				return nil
+			}
+			return json.Unmarshal(raw, out)
+		}
+
+		wait, retry := c.shouldRetry(ctx, method, attempt, err)
+		if !retry {
+			return err
+		}
+		logging.FromContext(ctx).Warn("upstream request failed, retrying",
+			"method", method, "host", h.name, "attempt", attempt+1, "max_attempts", c.opts.Retries+1,
+			"backoff", wait.Round(time.Millisecond), "err", err)
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// do performs a single attempt and returns the response body.
+func (c *Client) do(ctx context.Context, h *host, method, url string, headers map[string]string, payload []byte) ([]byte, error) {
+	c.mu.Lock()
+	service := h.service
+	c.mu.Unlock()
+
+	release, err := h.acquire(ctx)
+	if err != nil {
+		if errors.Is(err, ErrCircuitOpen) {
+			metrics.Upstream(service, method, "circuit_open", 0)
+		}
+		return nil, err
 	}
+	defer release()
 
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return err
+		h.cancelProbe()
+		return nil, err
 	}
 
-	if reqBody != nil {
+	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
+	logger := logging.FromContext(ctx).With("method", method, "host", h.name, "path", req.URL.Path)
+	start := time.Now()
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
-		return err
+		metrics.Upstream(service, method, "error", time.Since(start))
+		logger.Warn("upstream request error", "latency", time.Since(start), "err", err)
+		// The caller giving up says nothing about the upstream's health.
+		if ctx.Err() != nil {
+			h.cancelProbe()
+		} else {
+			h.record(false)
+		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	raw, _ := io.ReadAll(resp.Body)
+	h.record(resp.StatusCode < 500)
+	metrics.Upstream(service, method, statusClass(resp.StatusCode), time.Since(start))
+
+	level := slog.LevelDebug
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	logger.Log(ctx, level, "upstream request", "status", resp.StatusCode, "latency", time.Since(start))
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		// keep it short (don’t dump huge HTML into Discord)
@@ -56,11 +182,88 @@ func (c *Client) DoJSON(ctx context.Context, method, url string, headers map[str
 		if len(msg) > 300 {
 			msg = msg[:300] + "..."
 		}
-		return fmt.Errorf("http %d: %s", resp.StatusCode, msg)
+		return nil, &StatusError{
+			Method:     method,
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			Body:       msg,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	return raw, nil
+}
+
+// shouldRetry decides whether attempt (0-based) may be retried and how long
+// to wait first. Non-idempotent requests are only retried on 429, where the
+// upstream is known not to have processed them.
+func (c *Client) shouldRetry(ctx context.Context, method string, attempt int, err error) (time.Duration, bool) {
+	if attempt >= c.opts.Retries || ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
+		return 0, false
+	}
+
+	wait := c.backoff(attempt)
+	var se *StatusError
+	if errors.As(err, &se) {
+		if !se.Temporary() || (se.StatusCode != 429 && !idempotent(method)) {
+			return 0, false
+		}
+		if se.RetryAfter > 0 {
+			if c.opts.RetryMaxBackoff > 0 && se.RetryAfter > c.opts.RetryMaxBackoff {
+				return 0, false // not worth holding a Discord interaction for
+			}
+			wait = se.RetryAfter
+		}
+	} else if !idempotent(method) {
+		return 0, false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return 0, false
+	}
+	return wait, true
+}
+
+// backoff is exponential with jitter: a random duration in [d/2, d].
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.opts.RetryBackoff
+	if d <= 0 {
+		d = 500 * time.Millisecond
+	}
+	for i := 0; i < attempt; i++ {
+		d *= 2
+		if c.opts.RetryMaxBackoff > 0 && d >= c.opts.RetryMaxBackoff {
+			d = c.opts.RetryMaxBackoff
+			break
+		}
 	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
 
-	if out == nil {
-		return nil
+func statusClass(code int) string {
+	return strconv.Itoa(code/100) + "xx"
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter understands both delay-seconds and HTTP-date values.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
 	}
-	return json.Unmarshal(raw, out)
+	return 0
 }
@@ -0,0 +1,184 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingServer answers every request with the status returned by status
+// and counts the requests it saw.
+func countingServer(t *testing.T, status func() int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(status())
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestDoJSONRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		attempts int32
+	}{
+		{"GET 500 is retried", http.MethodGet, 500, 3},
+		{"GET 503 is retried", http.MethodGet, 503, 3},
+		{"GET 429 is retried", http.MethodGet, 429, 3},
+		{"POST 429 is retried", http.MethodPost, 429, 3},
+		{"POST 500 is not retried", http.MethodPost, 500, 1},
+		{"GET 400 is not retried", http.MethodGet, 400, 1},
+		{"GET 404 is not retried", http.MethodGet, 404, 1},
+		{"GET 200 succeeds first time", http.MethodGet, 200, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := countingServer(t, func() int { return tt.status })
+			c := New(Options{Retries: 2, RetryBackoff: time.Millisecond})
+
+			err := c.DoJSON(context.Background(), tt.method, srv.URL, nil, nil, nil)
+			if got := hits.Load(); got != tt.attempts {
+				t.Errorf("attempts = %d, want %d", got, tt.attempts)
+			}
+			if tt.status < 300 {
+				if err != nil {
+					t.Errorf("DoJSON() = %v, want nil", err)
+				}
+				return
+			}
+			if !IsStatus(err, tt.status) {
+				t.Errorf("DoJSON() = %v, want http %d", err, tt.status)
+			}
+		})
+	}
+}
+
+func TestDoJSONRetryAfterTooLong(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(Options{Retries: 2, RetryBackoff: time.Millisecond, RetryMaxBackoff: time.Second})
+	if err := c.DoJSON(context.Background(), http.MethodGet, srv.URL, nil, nil, nil); !IsStatus(err, 429) {
+		t.Fatalf("DoJSON() = %v, want http 429", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1 when Retry-After exceeds the max backoff", got)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+
+	tests := []struct {
+		name        string
+		probeStatus int
+		wantOpen    bool // circuit open again after the probe
+	}{
+		{"successful probe closes the circuit", http.StatusOK, false},
+		{"failed probe re-opens the circuit", http.StatusBadGateway, true},
+		{"4xx probe closes the circuit", http.StatusNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status atomic.Int32
+			status.Store(http.StatusInternalServerError)
+			srv, hits := countingServer(t, func() int { return int(status.Load()) })
+			c := New(Options{BreakerThreshold: 2, BreakerCooldown: cooldown})
+			get := func() error {
+				return c.DoJSON(context.Background(), http.MethodGet, srv.URL, nil, nil, nil)
+			}
+
+			// two failures open the circuit
+			for range 2 {
+				if err := get(); !IsStatus(err, 500) {
+					t.Fatalf("DoJSON() = %v, want http 500", err)
+				}
+			}
+			if err := get(); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("DoJSON() with open circuit = %v, want ErrCircuitOpen", err)
+			}
+			if got := hits.Load(); got != 2 {
+				t.Fatalf("upstream saw %d requests, want 2 while the circuit is open", got)
+			}
+
+			// half-open after the cooldown: one probe goes through
+			time.Sleep(cooldown + 10*time.Millisecond)
+			status.Store(int32(tt.probeStatus))
+			_ = get()
+			if got := hits.Load(); got != 3 {
+				t.Fatalf("upstream saw %d requests, want 3 after the probe", got)
+			}
+
+			err := get()
+			if open := errors.Is(err, ErrCircuitOpen); open != tt.wantOpen {
+				t.Errorf("DoJSON() after probe = %v, want circuit open %v", err, tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+
+	var failing atomic.Bool
+	failing.Store(true)
+	var probe sync.Once
+	probeStarted := make(chan struct{})
+	releaseProbe := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		probe.Do(func() {
+			close(probeStarted)
+			<-releaseProbe
+		})
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(Options{BreakerThreshold: 1, BreakerCooldown: cooldown})
+	get := func() error {
+		return c.DoJSON(context.Background(), http.MethodGet, srv.URL, nil, nil, nil)
+	}
+
+	if err := get(); !IsStatus(err, 500) {
+		t.Fatalf("DoJSON() = %v, want http 500", err)
+	}
+	time.Sleep(cooldown + 10*time.Millisecond)
+	failing.Store(false)
+
+	probeDone := make(chan error, 1)
+	go func() { probeDone <- get() }()
+	<-probeStarted
+
+	if err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("DoJSON() during probe = %v, want ErrCircuitOpen", err)
+	}
+
+	close(releaseProbe)
+	if err := <-probeDone; err != nil {
+		t.Fatalf("probe = %v, want nil", err)
+	}
+	if err := get(); err != nil {
+		t.Errorf("DoJSON() after successful probe = %v, want nil", err)
+	}
+}
@@ -0,0 +1,138 @@
+package limits
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/config"
+	"github.com/KevinHaeusler/go-haruki/bot/util"
+)
+
+// CooldownMessage is the ephemeral reply while a command is cooling down,
+// taking the command name and the Unix time the cooldown ends. Discord
+// renders the timestamp relative to each reader ("in 40 seconds").
+const CooldownMessage = "⏳ You can use `/%s` again <t:%d:R>."
+
+// BusyMessage is the reply when all release search slots are taken.
+const BusyMessage = "🚦 Too many release searches are running right now. Please try again in a minute."
+
+// Limiter enforces per-user command cooldowns and caps how many release
+// searches run at once. Members with the Administrator permission have no
+// cooldowns.
+type Limiter struct {
+	cooldowns map[string]config.CooldownRule
+	searches  chan struct{} // nil when unlimited
+
+	mu    sync.Mutex
+	until map[string]time.Time // command|user -> end of cooldown
+}
+
+// New builds a limiter from the config. commandNames lists the registered
+// slash commands so typos in the config are reported instead of silently
+// ignored.
+func New(cfg config.LimitsConfig, commandNames []string) (*Limiter, error) {
+	known := make(map[string]bool, len(commandNames))
+	for _, n := range commandNames {
+		known[n] = true
+	}
+	var unknown []string
+	for name := range cfg.Cooldowns {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("limits: unknown cooldown commands %v", unknown)
+	}
+
+	l := &Limiter{cooldowns: cfg.Cooldowns, until: make(map[string]time.Time)}
+	if cfg.MaxSearches > 0 {
+		l.searches = make(chan struct{}, cfg.MaxSearches)
+	}
+	return l, nil
+}
+
+// Begin starts the user's cooldown for command. If one is still running, ok
+// is false and until is when it ends.
+func (l *Limiter) Begin(i *discordgo.InteractionCreate, command string) (until time.Time, ok bool) {
+	d := l.cooldown(i, command)
+	if d <= 0 {
+		return time.Time{}, true
+	}
+	key := command + "|" + util.InteractionUserID(i)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if end := l.until[key]; now.Before(end) {
+		return end, false
+	}
+	for k, end := range l.until {
+		if !now.Before(end) {
+			delete(l.until, k)
+		}
+	}
+	l.until[key] = now.Add(d)
+	return time.Time{}, true
+}
+
+// Cancel ends the user's cooldown for command early, e.g. when the run
+// failed on our side.
+func (l *Limiter) Cancel(i *discordgo.InteractionCreate, command string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.until, command+"|"+util.InteractionUserID(i))
+}
+
+// TrySearch takes a release search slot without waiting. When ok, release
+// must be called once the search is done.
+func (l *Limiter) TrySearch() (release func(), ok bool) {
+	if l == nil || l.searches == nil {
+		return func() {}, true
+	}
+	select {
+	case l.searches <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-l.searches }) }, true
+	default:
+		return nil, false
+	}
+}
+
+// cooldown returns the cooldown that applies to the interaction's user.
+func (l *Limiter) cooldown(i *discordgo.InteractionCreate, command string) time.Duration {
+	rule, ok := l.cooldowns[command]
+	if !ok {
+		return 0
+	}
+	member := i.Member
+	if member != nil && member.Permissions&discordgo.PermissionAdministrator != 0 {
+		return 0
+	}
+
+	d, overridden := time.Duration(0), false
+	pick := func(c config.Duration) {
+		if !overridden || c.Duration < d {
+			d, overridden = c.Duration, true
+		}
+	}
+	if c, ok := rule.Users[util.InteractionUserID(i)]; ok {
+		pick(c)
+	}
+	if member != nil {
+		for _, role := range member.Roles {
+			if c, ok := rule.Roles[role]; ok {
+				pick(c)
+			}
+		}
+	}
+	if !overridden {
+		return rule.Default.Duration
+	}
+	return d
+}
@@ -0,0 +1,142 @@
+// Package metrics holds the bot's Prometheus collectors. Everything is
+// registered on a private registry served by Handler.
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Interaction outcomes.
+const (
+	OutcomeOK        = "ok"
+	OutcomeError     = "error"
+	OutcomeDenied    = "denied"
+	OutcomeThrottled = "throttled"
+	OutcomeUnhandled = "unhandled"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	interactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "haruki",
+		Name:      "interactions_total",
+		Help:      "Slash commands, component, modal and autocomplete interactions by kind, name and outcome.",
+	}, []string{"kind", "name", "outcome"})
+
+	interactionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "haruki",
+		Name:      "interaction_duration_seconds",
+		Help:      "Time spent in interaction handlers.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"kind", "name"})
+
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "haruki",
+		Name:      "upstream_requests_total",
+		Help:      "HTTP requests to Jellyseerr, Radarr, Sonarr and Tautulli by result (2xx, 4xx, 5xx, error, circuit_open).",
+	}, []string{"service", "method", "result"})
+
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "haruki",
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of upstream HTTP requests.",
+		Buckets:   []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"service", "method"})
+
+	webhookEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "haruki",
+		Name:      "webhook_events_total",
+		Help:      "Jellyseerr webhook notifications received, by event.",
+	}, []string{"event"})
+
+	webhookSuppressed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "haruki",
+		Name:      "webhook_suppressed_total",
+		Help:      "Webhook notifications dropped as duplicates, by event.",
+	}, []string{"event"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		interactions,
+		interactionDuration,
+		upstreamRequests,
+		upstreamDuration,
+		webhookEvents,
+		webhookSuppressed,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Interaction records one handled interaction. kind is "command",
+// "component", "modal" or "autocomplete".
+func Interaction(kind, name, outcome string, d time.Duration) {
+	interactions.WithLabelValues(kind, name, outcome).Inc()
+	if outcome == OutcomeOK || outcome == OutcomeError {
+		interactionDuration.WithLabelValues(kind, name).Observe(d.Seconds())
+	}
+}
+
+// Upstream records one upstream HTTP attempt. d is zero when no request was sent.
+func Upstream(service, method, result string, d time.Duration) {
+	upstreamRequests.WithLabelValues(service, method, result).Inc()
+	if d > 0 {
+		upstreamDuration.WithLabelValues(service, method).Observe(d.Seconds())
+	}
+}
+
+func WebhookEvent(event string) {
+	webhookEvents.WithLabelValues(eventLabel(event)).Inc()
+}
+
+func WebhookSuppressed(event string) {
+	webhookSuppressed.WithLabelValues(eventLabel(event)).Inc()
+}
+
+var knownEvents = map[string]bool{
+	"TEST_NOTIFICATION":   true,
+	"MEDIA_PENDING":       true,
+	"MEDIA_APPROVED":      true,
+	"MEDIA_AUTO_APPROVED": true,
+	"MEDIA_AVAILABLE":     true,
+	"MEDIA_DECLINED":      true,
+	"MEDIA_FAILED":        true,
+	"MEDIA_REQUESTED":     true,
+	"ISSUE_REPORTED":      true,
+	"ISSUE_COMMENT":       true,
+	"ISSUE_RESOLVED":      true,
+	"ISSUE_REOPENED":      true,
+}
+
+// eventLabel keeps label cardinality bounded: the event name comes from the
+// webhook body, so anything unexpected is counted as "OTHER".
+func eventLabel(event string) string {
+	e := strings.ToUpper(strings.TrimSpace(event))
+	if knownEvents[e] {
+		return e
+	}
+	return "OTHER"
+}
+
+// SessionGauge exports the number of live sessions in a store.
+func SessionGauge(store string, count func() int) {
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   "haruki",
+		Name:        "sessions_active",
+		Help:        "Interactive sessions that have not expired, by store.",
+		ConstLabels: prometheus.Labels{"store": store},
+	}, func() float64 { return float64(count()) }))
+}
@@ -0,0 +1,154 @@
+package permissions
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/KevinHaeusler/go-haruki/bot/config"
+)
+
+// Sub-actions that can be restricted separately from the command they belong to.
+const (
+	// FixMissingApprove is pressing "Approve" to grab a release in /plex-fix-missing.
+	FixMissingApprove = "plex-fix-missing.approve"
+	// FixMissingApproveRejected is grabbing a release Radarr/Sonarr rejected.
+	FixMissingApproveRejected = "plex-fix-missing.approve-rejected"
+	// Request4K is requesting the 4K version in /plex-request.
+	Request4K = "plex-request.4k"
+	// RequestAdvanced is choosing the server, quality profile and root folder
+	// of a request in /plex-request.
+	RequestAdvanced = "plex-request.advanced"
+	// JellyLinkOthers is linking (or relinking) a Discord user other than yourself.
+	JellyLinkOthers = "jelly-link.others"
+	// AbortAnySession is aborting an interactive session started by someone else.
+	AbortAnySession = "session.abort-any"
+	// UseAnySession is driving someone else's interactive session (selecting,
+	// paging, confirming). Every use is logged.
+	UseAnySession = "session.use-any"
+)
+
+var adminOnly = config.PermissionRule{Permissions: []string{"administrator"}}
+
+// actionDefaults apply when an action has no rule in the config. Every
+// action is admin-only until the config opens it up.
+var actionDefaults = map[string]config.PermissionRule{
+	FixMissingApprove:         adminOnly,
+	FixMissingApproveRejected: adminOnly,
+	Request4K:                 adminOnly,
+	RequestAdvanced:           adminOnly,
+	JellyLinkOthers:           adminOnly,
+	AbortAnySession:           adminOnly,
+	UseAnySession:             adminOnly,
+}
+
+// DeniedMessage is the ephemeral reply for any permission denial.
+const DeniedMessage = "⛔ You don't have permission to %s."
+
+// Policy decides who may run which command or action. Members with the
+// Administrator permission are always allowed.
+type Policy struct {
+	commands map[string]config.PermissionRule
+	actions  map[string]config.PermissionRule
+}
+
+// New builds a policy from the config. commandNames lists the registered slash
+// commands so typos in the config are reported instead of silently ignored.
+func New(cfg config.Config, commandNames []string) (*Policy, error) {
+	p := &Policy{
+		commands: make(map[string]config.PermissionRule),
+		actions:  make(map[string]config.PermissionRule),
+	}
+
+	// roles.plex keeps working as shorthand for the two Plex commands.
+	if cfg.Roles.Plex != "" {
+		rule := config.PermissionRule{Roles: []string{cfg.Roles.Plex}}
+		p.commands["plex-request"] = rule
+		p.commands["plex-fix-missing"] = rule
+	}
+
+	known := make(map[string]bool, len(commandNames))
+	for _, n := range commandNames {
+		known[n] = true
+	}
+	var unknown []string
+	for name, rule := range cfg.Permissions.Commands {
+		if !known[name] {
+			unknown = append(unknown, "command "+name)
+			continue
+		}
+		p.commands[name] = rule
+	}
+
+	for name, rule := range actionDefaults {
+		p.actions[name] = rule
+	}
+	for name, rule := range cfg.Permissions.Actions {
+		if _, ok := actionDefaults[name]; !ok {
+			unknown = append(unknown, "action "+name)
+			continue
+		}
+		p.actions[name] = rule
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("permissions: unknown %v", unknown)
+	}
+	return p, nil
+}
+
+// CanUseCommand reports whether the interaction's user may run the slash command.
+func (p *Policy) CanUseCommand(i *discordgo.InteractionCreate, command string) bool {
+	return allows(p.commands[command], i)
+}
+
+// Can reports whether the interaction's user may perform the sub-action.
+func (p *Policy) Can(i *discordgo.InteractionCreate, action string) bool {
+	rule, ok := p.actions[action]
+	if !ok {
+		rule = adminOnly
+	}
+	return allows(rule, i)
+}
+
+func allows(rule config.PermissionRule, i *discordgo.InteractionCreate) bool {
+	if rule.IsEmpty() {
+		return true
+	}
+
+	member := i.Member
+	userID := ""
+	if member != nil && member.User != nil {
+		userID = member.User.ID
+	} else if i.User != nil {
+		userID = i.User.ID
+	}
+
+	if member != nil && member.Permissions&discordgo.PermissionAdministrator != 0 {
+		return true
+	}
+
+	for _, id := range rule.Users {
+		if id == userID {
+			return true
+		}
+	}
+	if member == nil {
+		return false
+	}
+	for _, want := range rule.Roles {
+		for _, have := range member.Roles {
+			if want == have {
+				return true
+			}
+		}
+	}
+	for _, name := range rule.Permissions {
+		if bit, ok := config.PermissionBit(name); ok && member.Permissions&bit != 0 {
+			return true
+		}
+	}
+	return false
+}
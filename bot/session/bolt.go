@@ -0,0 +1,191 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DB is a bbolt file holding one bucket per session store.
+type DB struct {
+	bolt *bolt.DB
+}
+
+// Open opens (or creates) the session database at path.
+func Open(path string) (*DB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open session db %s: %w", path, err)
+	}
+	return &DB{bolt: db}, nil
+}
+
+func (db *DB) Close() error {
+	return db.bolt.Close()
+}
+
+// boltStore serializes sessions as JSON, so T must round-trip through
+// encoding/json. Entries that no longer decode (e.g. after an upgrade
+// changed T) are dropped.
+type boltStore[T any] struct {
+	hooks[T]
+
+	db     *bolt.DB
+	bucket []byte
+	ttl    time.Duration
+}
+
+func newBoltStore[T any](db *DB, name string, ttl time.Duration, opts ...Option) *boltStore[T] {
+	return &boltStore[T]{hooks: newHooks[T](opts), db: db.bolt, bucket: []byte(name), ttl: ttl}
+}
+
+func (s *boltStore[T]) Get(id string) *T {
+	data, _ := s.GetWithExpiration(id)
+	return data
+}
+
+func (s *boltStore[T]) GetWithExpiration(id string) (*T, bool) {
+	var (
+		out  *T
+		gone map[string]T
+	)
+	s.update(func(b *bolt.Bucket) error {
+		sess, ok := s.decode(b, id)
+		if !ok {
+			return nil
+		}
+		if s.now().After(sess.ExpiresAt) {
+			gone = map[string]T{id: sess.Data}
+			return b.Delete([]byte(id))
+		}
+		out = &sess.Data
+		return nil
+	})
+	s.expired(gone)
+	return out, gone != nil
+}
+
+func (s *boltStore[T]) Set(id string, data T) {
+	s.update(func(b *bolt.Bucket) error {
+		return s.put(b, id, Session[T]{Data: data, ExpiresAt: s.now().Add(s.ttl)})
+	})
+}
+
+func (s *boltStore[T]) Clear(id string) {
+	s.update(func(b *bolt.Bucket) error {
+		return b.Delete([]byte(id))
+	})
+}
+
+func (s *boltStore[T]) Touch(id string) {
+	s.update(func(b *bolt.Bucket) error {
+		sess, ok := s.decode(b, id)
+		if !ok {
+			return nil
+		}
+		sess.ExpiresAt = s.now().Add(s.ttl)
+		return s.put(b, id, sess)
+	})
+}
+
+func (s *boltStore[T]) TTL() time.Duration { return s.ttl }
+
+func (s *boltStore[T]) Len() int {
+	n := 0
+	now := s.now()
+	s.Each(func(_ string, sess Session[T]) {
+		if now.Before(sess.ExpiresAt) {
+			n++
+		}
+	})
+	return n
+}
+
+func (s *boltStore[T]) Each(fn func(id string, sess Session[T])) {
+	all := make(map[string]Session[T])
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var sess Session[T]
+			if err := json.Unmarshal(v, &sess); err == nil {
+				all[string(k)] = sess
+			}
+			return nil
+		})
+	})
+	for k, v := range all {
+		fn(k, v)
+	}
+}
+
+func (s *boltStore[T]) Sweep() int {
+	now := s.now()
+	gone := make(map[string]T)
+	s.update(func(b *bolt.Bucket) error {
+		var drop [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var sess Session[T]
+			if err := json.Unmarshal(v, &sess); err != nil {
+				drop = append(drop, append([]byte(nil), k...))
+				return nil
+			}
+			if now.After(sess.ExpiresAt) {
+				gone[string(k)] = sess.Data
+				drop = append(drop, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range drop {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	s.expired(gone)
+	return len(gone)
+}
+
+func (s *boltStore[T]) update(fn func(b *bolt.Bucket) error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(s.bucket)
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+	if err != nil {
+		slog.Error("session store write failed", "store", string(s.bucket), "err", err)
+	}
+}
+
+func (s *boltStore[T]) decode(b *bolt.Bucket, id string) (Session[T], bool) {
+	var sess Session[T]
+	raw := b.Get([]byte(id))
+	if raw == nil {
+		return sess, false
+	}
+	if err := json.Unmarshal(raw, &sess); err != nil {
+		slog.Warn("dropping undecodable session", "store", string(s.bucket), "session", id, "err", err)
+		_ = b.Delete([]byte(id))
+		return sess, false
+	}
+	return sess, true
+}
+
+func (s *boltStore[T]) put(b *bolt.Bucket, id string, sess Session[T]) error {
+	raw, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(id), raw)
+}
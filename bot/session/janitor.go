@@ -0,0 +1,40 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+// Sweeper removes expired sessions, running their OnExpire callbacks.
+type Sweeper interface {
+	Sweep() int
+}
+
+// RunJanitor sweeps all stores every interval until ctx is done, starting
+// immediately so sessions that expired while the bot was down are closed.
+func RunJanitor(ctx context.Context, interval time.Duration, stores ...Sweeper) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	SweepOn(ctx, t.C, stores...)
+}
+
+// SweepOn sweeps all stores once right away and again on every tick until
+// ctx is done. RunJanitor drives it from a ticker; tests can send ticks
+// themselves.
+func SweepOn(ctx context.Context, tick <-chan time.Time, stores ...Sweeper) {
+	sweep := func() {
+		for _, s := range stores {
+			s.Sweep()
+		}
+	}
+	sweep()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			sweep()
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is a process-local Store; sessions are lost on restart.
+type MemoryStore[T any] struct {
+	hooks[T]
+
+	mu       sync.RWMutex
+	sessions map[string]*Session[T]
+	ttl      time.Duration
+}
+
+func NewMemoryStore[T any](ttl time.Duration, opts ...Option) *MemoryStore[T] {
+	return &MemoryStore[T]{
+		hooks:    newHooks[T](opts),
+		sessions: make(map[string]*Session[T]),
+		ttl:      ttl,
+	}
+}
+
+func (s *MemoryStore[T]) Get(id string) *T {
+	data, _ := s.GetWithExpiration(id)
+	return data
+}
+
+func (s *MemoryStore[T]) GetWithExpiration(id string) (*T, bool) {
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false
+	}
+	if s.now().After(sess.ExpiresAt) {
+		delete(s.sessions, id)
+		s.mu.Unlock()
+		s.expired(map[string]T{id: sess.Data})
+		return nil, true
+	}
+	s.mu.Unlock()
+	return &sess.Data, false
+}
+
+func (s *MemoryStore[T]) Set(id string, data T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = &Session[T]{
+		Data:      data,
+		ExpiresAt: s.now().Add(s.ttl),
+	}
+}
+
+func (s *MemoryStore[T]) Clear(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+func (s *MemoryStore[T]) TTL() time.Duration { return s.ttl }
+
+func (s *MemoryStore[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := s.now()
+	n := 0
+	for _, sess := range s.sessions {
+		if now.Before(sess.ExpiresAt) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *MemoryStore[T]) Touch(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[id]; ok {
+		sess.ExpiresAt = s.now().Add(s.ttl)
+	}
+}
+
+func (s *MemoryStore[T]) Each(fn func(id string, sess Session[T])) {
+	s.mu.RLock()
+	snapshot := make(map[string]Session[T], len(s.sessions))
+	for k, v := range s.sessions {
+		snapshot[k] = *v
+	}
+	s.mu.RUnlock()
+	for k, v := range snapshot {
+		fn(k, v)
+	}
+}
+
+func (s *MemoryStore[T]) Sweep() int {
+	now := s.now()
+	gone := make(map[string]T)
+	s.mu.Lock()
+	for id, sess := range s.sessions {
+		if now.After(sess.ExpiresAt) {
+			gone[id] = sess.Data
+			delete(s.sessions, id)
+		}
+	}
+	s.mu.Unlock()
+	s.expired(gone)
+	return len(gone)
+}
@@ -1,6 +1,8 @@
 package session
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"sync"
 	"time"
 )
@@ -10,57 +12,93 @@ type Session[T any] struct {
 	ExpiresAt time.Time
 }
 
-type Store[T any] struct {
-	mu       sync.RWMutex
-	sessions map[string]*Session[T]
-	ttl      time.Duration
+// Store keeps sessions by an opaque ID (see NewID) that the flow embeds in
+// its component CustomIDs, so one user can have several flows open at once.
+// Sessions expire ttl after their last Set or Touch.
+type Store[T any] interface {
+	Get(id string) *T
+	// GetWithExpiration also reports whether the session existed but has
+	// expired; the expired session is removed and OnExpire callbacks run.
+	GetWithExpiration(id string) (*T, bool)
+	Set(id string, data T)
+	// Clear removes a session without running OnExpire callbacks.
+	Clear(id string)
+	Touch(id string)
+	// Len returns the number of sessions that have not expired yet.
+	Len() int
+	// Each calls fn for every stored session, including expired ones.
+	Each(fn func(id string, sess Session[T]))
+	// OnExpire registers fn to run once for every session that times out.
+	OnExpire(fn func(id string, data T))
+	// TTL is how long a session lives without a Set or Touch.
+	TTL() time.Duration
+
+	Sweeper
 }
 
-func NewStore[T any](ttl time.Duration) *Store[T] {
-	return &Store[T]{
-		sessions: make(map[string]*Session[T]),
-		ttl:      ttl,
-	}
+// NewID returns a random session ID, short enough to fit in a CustomID.
+func NewID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
 }
 
-func (s *Store[T]) Get(userID string) *T {
-	data, _ := s.GetWithExpiration(userID)
-	return data
+// Option configures a store.
+type Option func(*options)
+
+type options struct {
+	now func() time.Time
 }
 
-func (s *Store[T]) GetWithExpiration(userID string) (*T, bool) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	sess, ok := s.sessions[userID]
-	if !ok {
-		return nil, false
-	}
-	if time.Now().After(sess.ExpiresAt) {
-		delete(s.sessions, userID)
-		return nil, true
+// WithClock replaces time.Now, so expiry can be driven by a fake clock.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// New returns a store persisted in db under name, or an in-memory store if
+// db is nil.
+func New[T any](db *DB, name string, ttl time.Duration, opts ...Option) Store[T] {
+	if db == nil {
+		return NewMemoryStore[T](ttl, opts...)
 	}
-	return &sess.Data, false
+	return newBoltStore[T](db, name, ttl, opts...)
+}
+
+// hooks holds what both store implementations share: the clock and the
+// expiry callbacks.
+type hooks[T any] struct {
+	now func() time.Time
+
+	mu       sync.RWMutex
+	onExpire []func(id string, data T)
 }
 
-func (s *Store[T]) Set(userID string, data T) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.sessions[userID] = &Session[T]{
-		Data:      data,
-		ExpiresAt: time.Now().Add(s.ttl),
+func newHooks[T any](opts []Option) hooks[T] {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
 	}
+	return hooks[T]{now: o.now}
 }
 
-func (s *Store[T]) Clear(userID string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	delete(s.sessions, userID)
+func (h *hooks[T]) OnExpire(fn func(id string, data T)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onExpire = append(h.onExpire, fn)
 }
 
-func (s *Store[T]) Touch(userID string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if sess, ok := s.sessions[userID]; ok {
-		sess.ExpiresAt = time.Now().Add(s.ttl)
+// expired runs the callbacks for sessions already removed from the store.
+// It must be called without holding the store's lock.
+func (h *hooks[T]) expired(sessions map[string]T) {
+	if len(sessions) == 0 {
+		return
+	}
+	h.mu.RLock()
+	fns := h.onExpire
+	h.mu.RUnlock()
+	for id, data := range sessions {
+		for _, fn := range fns {
+			fn(id, data)
+		}
 	}
 }
@@ -0,0 +1,214 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testTTL = 5 * time.Minute
+
+// fakeClock is a hand-advanced clock for WithClock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// backends returns a constructor for every Store implementation.
+func backends(t *testing.T) map[string]func(clock *fakeClock) Store[string] {
+	return map[string]func(clock *fakeClock) Store[string]{
+		"memory": func(clock *fakeClock) Store[string] {
+			return New[string](nil, "test", testTTL, WithClock(clock.Now))
+		},
+		"bolt": func(clock *fakeClock) Store[string] {
+			db, err := Open(filepath.Join(t.TempDir(), "sessions.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = db.Close() })
+			return New[string](db, "test", testTTL, WithClock(clock.Now))
+		},
+	}
+}
+
+// expiries counts OnExpire calls per session.
+func expiries(s Store[string]) func() map[string]int {
+	var mu sync.Mutex
+	got := make(map[string]int)
+	s.OnExpire(func(id string, _ string) {
+		mu.Lock()
+		defer mu.Unlock()
+		got[id]++
+	})
+	return func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		out := make(map[string]int, len(got))
+		for k, v := range got {
+			out[k] = v
+		}
+		return out
+	}
+}
+
+func TestSweepExpiresIdleSessions(t *testing.T) {
+	for name, newStore := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := newFakeClock()
+			s := newStore(clock)
+			fired := expiries(s)
+
+			s.Set("old", "a")
+			clock.Advance(testTTL / 2)
+			s.Set("new", "b")
+			clock.Advance(testTTL/2 + time.Second)
+
+			if n := s.Sweep(); n != 1 {
+				t.Fatalf("Sweep() = %d, want 1", n)
+			}
+			if got := s.Get("old"); got != nil {
+				t.Errorf("Get(old) = %q, want expired", *got)
+			}
+			if got := s.Get("new"); got == nil || *got != "b" {
+				t.Errorf("Get(new) = %v, want b", got)
+			}
+			if got := fired(); got["old"] != 1 || got["new"] != 0 {
+				t.Errorf("OnExpire calls = %v, want old once", got)
+			}
+		})
+	}
+}
+
+func TestOnExpireFiresOnce(t *testing.T) {
+	for name, newStore := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := newFakeClock()
+			s := newStore(clock)
+			fired := expiries(s)
+
+			s.Set("a", "a")
+			clock.Advance(testTTL + time.Second)
+
+			if _, expired := s.GetWithExpiration("a"); !expired {
+				t.Error("GetWithExpiration(a) expired = false, want true")
+			}
+			if _, expired := s.GetWithExpiration("a"); expired {
+				t.Error("second GetWithExpiration(a) expired = true, want false")
+			}
+			if n := s.Sweep(); n != 0 {
+				t.Errorf("Sweep() = %d, want 0", n)
+			}
+			if got := fired()["a"]; got != 1 {
+				t.Errorf("OnExpire calls = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestTouchExtendsTTL(t *testing.T) {
+	for name, newStore := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := newFakeClock()
+			s := newStore(clock)
+			fired := expiries(s)
+
+			s.Set("a", "a")
+			clock.Advance(testTTL - time.Second)
+			s.Touch("a")
+			clock.Advance(testTTL - time.Second)
+
+			if n := s.Sweep(); n != 0 {
+				t.Fatalf("Sweep() = %d after Touch, want 0", n)
+			}
+			if got := s.Get("a"); got == nil {
+				t.Fatal("Get(a) = nil after Touch, want session")
+			}
+
+			clock.Advance(2 * time.Second)
+			if n := s.Sweep(); n != 1 {
+				t.Errorf("Sweep() = %d once the touched TTL ran out, want 1", n)
+			}
+			if got := fired()["a"]; got != 1 {
+				t.Errorf("OnExpire calls = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestClearSkipsOnExpire(t *testing.T) {
+	for name, newStore := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := newFakeClock()
+			s := newStore(clock)
+			fired := expiries(s)
+
+			s.Set("a", "a")
+			s.Clear("a")
+			clock.Advance(testTTL + time.Second)
+
+			if n := s.Sweep(); n != 0 {
+				t.Errorf("Sweep() = %d, want 0", n)
+			}
+			if got := s.Len(); got != 0 {
+				t.Errorf("Len() = %d, want 0", got)
+			}
+			if got := fired(); len(got) != 0 {
+				t.Errorf("OnExpire calls = %v, want none", got)
+			}
+		})
+	}
+}
+
+func TestSweepOn(t *testing.T) {
+	clock := newFakeClock()
+	s := New[string](nil, "test", testTTL, WithClock(clock.Now))
+	expired := make(chan string, 4)
+	s.OnExpire(func(id string, _ string) { expired <- id })
+
+	// expired before the janitor starts: swept right away
+	s.Set("stale", "a")
+	clock.Advance(testTTL + time.Second)
+	s.Set("fresh", "b")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tick := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		SweepOn(ctx, tick, s)
+		close(done)
+	}()
+
+	if id := <-expired; id != "stale" {
+		t.Fatalf("first sweep expired %q, want stale", id)
+	}
+
+	clock.Advance(testTTL + time.Second)
+	tick <- clock.Now()
+	if id := <-expired; id != "fresh" {
+		t.Fatalf("sweep on tick expired %q, want fresh", id)
+	}
+
+	cancel()
+	<-done
+	if n := len(expired); n != 0 {
+		t.Errorf("%d extra expiries after cancel", n)
+	}
+}
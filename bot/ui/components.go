@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
 	"github.com/bwmarrin/discordgo"
@@ -18,9 +19,10 @@ func ResultsSelect(customID string, results []jellyseerr.MediaSummary, selectedI
 		}
 
 		opts = append(opts, discordgo.SelectMenuOption{
-			Label:   Truncate(label, 100),
-			Value:   strconv.Itoa(m.ID),
-			Default: m.ID == selectedID,
+			Label:       Truncate(label, 100),
+			Value:       strconv.Itoa(m.ID),
+			Description: resultDescription(m),
+			Default:     m.ID == selectedID,
 		})
 		if len(opts) == 25 {
 			break
@@ -36,6 +38,72 @@ func ResultsSelect(customID string, results []jellyseerr.MediaSummary, selectedI
 	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{menu}}
 }
 
+// SeasonSelect is a multi-select of TV seasons. Discord allows 25 options,
+// so only the latest 25 seasons are offered.
+func SeasonSelect(customID string, seasons []jellyseerr.Season, selected []int) discordgo.MessageComponent {
+	if len(seasons) > 25 {
+		seasons = seasons[len(seasons)-25:]
+	}
+	picked := make(map[int]bool, len(selected))
+	for _, n := range selected {
+		picked[n] = true
+	}
+
+	opts := make([]discordgo.SelectMenuOption, 0, len(seasons))
+	for _, s := range seasons {
+		label := s.Name
+		if label == "" {
+			label = fmt.Sprintf("Season %d", s.SeasonNumber)
+		}
+		desc := fmt.Sprintf("%d episodes", s.EpisodeCount)
+		if len(s.AirDate) >= 4 {
+			desc += " · " + s.AirDate[:4]
+		}
+		opts = append(opts, discordgo.SelectMenuOption{
+			Label:       Truncate(label, 100),
+			Value:       strconv.Itoa(s.SeasonNumber),
+			Description: desc,
+			Default:     picked[s.SeasonNumber],
+		})
+	}
+
+	minValues := 1
+	menu := discordgo.SelectMenu{
+		CustomID:    customID,
+		Placeholder: "Choose seasons…",
+		MinValues:   &minValues,
+		MaxValues:   len(opts),
+		Options:     opts,
+	}
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{menu}}
+}
+
+// resultDescription is "Movie · 2021 · ✅ Available", leaving out what's unknown.
+func resultDescription(m jellyseerr.MediaSummary) string {
+	parts := make([]string, 0, 3)
+	switch m.MediaType {
+	case "movie":
+		parts = append(parts, "Movie")
+	case "tv":
+		parts = append(parts, "TV")
+	}
+	if m.Year != "" {
+		parts = append(parts, m.Year)
+	}
+	if m.Status >= 2 {
+		parts = append(parts, mediaStatusLabel(m.Status))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// PageButtons are Prev and Next, disabled at either end.
+func PageButtons(prevID, nextID string, page, totalPages int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.Button{Label: "Prev", Style: discordgo.SecondaryButton, CustomID: prevID, Disabled: page <= 1},
+		discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: nextID, Disabled: page >= totalPages},
+	}
+}
+
 func ButtonsRow(buttons ...discordgo.MessageComponent) discordgo.MessageComponent {
 	return discordgo.ActionsRow{Components: buttons}
 }
@@ -48,6 +116,10 @@ func ConfirmButton(customID string) discordgo.Button {
 	return discordgo.Button{Label: "Request", Style: discordgo.SuccessButton, CustomID: customID}
 }
 
+func Confirm4KButton(customID string) discordgo.Button {
+	return discordgo.Button{Label: "Request 4K", Style: discordgo.SuccessButton, CustomID: customID}
+}
+
 func NotifyButton(customID string) discordgo.Button {
 	return discordgo.Button{Label: "Notify Me", Style: discordgo.PrimaryButton, CustomID: customID}
 }
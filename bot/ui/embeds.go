@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/KevinHaeusler/go-haruki/bot/clients/jellyseerr"
@@ -108,14 +110,20 @@ func JellyRequestListEmbed(user *discordgo.User, requests []jellyseerr.UserReque
 	return embed
 }
 
-func JellyResultListEmbed(query string) *discordgo.MessageEmbed {
-	return &discordgo.MessageEmbed{
+func JellyResultListEmbed(query string, page, totalPages int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
 		Title:       "🔎 Jellyseerr Search",
 		Description: fmt.Sprintf("Results for: **%s**\nSelect an item below.", query),
 	}
+	if totalPages > 1 {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Page %d of %d", page, totalPages)}
+	}
+	return embed
 }
 
-func JellyDetailEmbed(d jellyseerr.MediaDetail, mediaType string) *discordgo.MessageEmbed {
+// JellyDetailEmbed describes a search result. show4K adds the 4K availability
+// next to the standard one; quota, if set, is the requester's allowance.
+func JellyDetailEmbed(d jellyseerr.MediaDetail, mediaType string, show4K bool, quota *jellyseerr.Quota) *discordgo.MessageEmbed {
 	title := fmt.Sprintf("%s (%s)", d.DisplayTitle(mediaType), d.DisplayYear(mediaType))
 
 	poster := MissingPosterURL
@@ -144,11 +152,44 @@ func JellyDetailEmbed(d jellyseerr.MediaDetail, mediaType string) *discordgo.Mes
 		}
 	}
 
-	return &discordgo.MessageEmbed{
+	embed := &discordgo.MessageEmbed{
 		Title:       title,
 		Description: description.String(),
 		Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: poster},
 	}
+	if show4K {
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{Name: "Standard", Value: mediaStatusLabel(d.MediaInfo.Status), Inline: true},
+			{Name: "4K", Value: mediaStatusLabel(d.MediaInfo.Status4k), Inline: true},
+		}
+	}
+	if quota != nil {
+		unit := "requests"
+		if mediaType == "tv" {
+			unit = "seasons" // TV quotas count seasons
+		}
+		value := fmt.Sprintf("%d of %d %s left (per %d days)", quota.Remaining, quota.Limit, unit, quota.Days)
+		if quota.Restricted {
+			value = "🚫 " + value
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Request Quota", Value: value})
+	}
+	return embed
+}
+
+// mediaStatusLabel names a Jellyseerr media status.
+func mediaStatusLabel(status int) string {
+	switch status {
+	case 2:
+		return "⏳ Requested"
+	case 3:
+		return "⚙️ Processing"
+	case 4:
+		return "⚠️ Partially available"
+	case 5:
+		return "✅ Available"
+	}
+	return "Not requested"
 }
 
 func JellyAlreadyRequestedEmbed(d jellyseerr.MediaDetail, mediaType string) *discordgo.MessageEmbed {
@@ -248,7 +289,73 @@ func JellyPartialAvailabilityEmbed(d jellyseerr.MediaDetail, mediaType string) *
 	}
 }
 
-func JellyRequestSentEmbed(d jellyseerr.MediaDetail, mediaType, requester string, totalRequests int) *discordgo.MessageEmbed {
+// JellySeasonPickEmbed asks which seasons to request and lists the ones that
+// are already available or requested.
+func JellySeasonPickEmbed(d jellyseerr.MediaDetail, requestable int, is4k bool) *discordgo.MessageEmbed {
+	poster := MissingPosterURL
+	if d.PosterPath != "" {
+		poster = TMDBImageURL + d.PosterPath
+	}
+
+	desc := "Pick the seasons to request, or request all of them."
+	if requestable > 25 {
+		desc += fmt.Sprintf("\nOnly the latest 25 of %d seasons fit in the menu; **All Seasons** requests every one.", requestable)
+	}
+
+	status := d.SeasonStatus(is4k)
+	numbers := make([]int, 0, len(status))
+	for n := range status {
+		if n > 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	var taken strings.Builder
+	for _, n := range numbers {
+		taken.WriteString(fmt.Sprintf("Season %d: %s\n", n, mediaStatusLabel(status[n])))
+	}
+
+	author := "📺 Choose Seasons"
+	if is4k {
+		author = "📺 Choose Seasons (4K)"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s (%s)", d.DisplayTitle("tv"), d.DisplayYear("tv")),
+		Description: desc,
+		Color:       0x9c5db3,
+		Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: poster},
+		Author:      &discordgo.MessageEmbedAuthor{Name: author},
+	}
+	if taken.Len() > 0 {
+		embed.Fields = []*discordgo.MessageEmbedField{{
+			Name:  "Already available or requested",
+			Value: Truncate(taken.String(), 1024),
+		}}
+	}
+	return embed
+}
+
+// JellyAdvancedEmbed heads the Advanced step of a request.
+func JellyAdvancedEmbed(title string, server jellyseerr.Server) *discordgo.MessageEmbed {
+	tier := "Standard"
+	if server.Is4k {
+		tier = "4K"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: "Choose where the request goes. Anything you leave alone uses the server's defaults.",
+		Color:       0x9c5db3,
+		Author:      &discordgo.MessageEmbedAuthor{Name: "⚙️ Advanced Request"},
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Server", Value: nonEmpty(server.Name, fmt.Sprintf("#%d", server.ID)), Inline: true},
+			{Name: "Quality", Value: tier, Inline: true},
+		},
+	}
+}
+
+// JellyRequestSentEmbed confirms a request, including its tier and, for TV,
+// the requested seasons.
+func JellyRequestSentEmbed(d jellyseerr.MediaDetail, mediaType, requester string, totalRequests int, opts jellyseerr.RequestOptions) *discordgo.MessageEmbed {
 	poster := MissingPosterURL
 	if d.PosterPath != "" {
 		poster = TMDBImageURL + d.PosterPath
@@ -256,16 +363,66 @@ func JellyRequestSentEmbed(d jellyseerr.MediaDetail, mediaType, requester string
 
 	title := fmt.Sprintf("%s (%s)", d.DisplayTitle(mediaType), d.DisplayYear(mediaType))
 
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Requested By", Value: requester, Inline: true},
+		{Name: "Request Status", Value: "Processing", Inline: true},
+		{Name: "Total Requests", Value: fmt.Sprintf("%d", totalRequests), Inline: true},
+	}
+	tier := "Standard"
+	if opts.Is4k {
+		tier = "4K"
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{Name: "Quality", Value: tier, Inline: true})
+	if len(opts.Seasons) > 0 {
+		names := make([]string, 0, len(opts.Seasons))
+		for _, n := range opts.Seasons {
+			names = append(names, strconv.Itoa(n))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Seasons", Value: Truncate(strings.Join(names, ", "), 1024)})
+	}
+
 	return &discordgo.MessageEmbed{
 		Title:       title,
 		Description: Truncate(d.Overview, 4000),
 		Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: poster},
 		Color:       0x9c5db3,
-		Author:      &discordgo.MessageEmbedAuthor{Name: fmt.Sprintf("%s Request Sent", cases.Title(language.English).String(mediaType))},
-		Fields: []*discordgo.MessageEmbedField{
-			{Name: "Requested By", Value: requester, Inline: true},
-			{Name: "Request Status", Value: "Processing", Inline: true},
-			{Name: "Total Requests", Value: fmt.Sprintf("%d", totalRequests), Inline: true},
-		},
+		Author:      &discordgo.MessageEmbedAuthor{Name: fmt.Sprintf("%s %sRequest Sent", cases.Title(language.English).String(mediaType), map[bool]string{true: "4K "}[opts.Is4k])},
+		Fields:      fields,
+	}
+}
+
+// JellyDiscoverEmbed shows one title of a /plex-discover list: the pos-th of
+// total on page of totalPages.
+func JellyDiscoverEmbed(heading string, item jellyseerr.DiscoverItem, pos, total, page, totalPages int) *discordgo.MessageEmbed {
+	poster := MissingPosterURL
+	if item.PosterPath != "" {
+		poster = TMDBImageURL + item.PosterPath
+	}
+
+	title := item.Title
+	if item.Year != "" {
+		title = fmt.Sprintf("%s (%s)", item.Title, item.Year)
+	}
+	kind := "Movie"
+	if item.MediaType == "tv" {
+		kind = "TV"
+	}
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Type", Value: kind, Inline: true},
+		{Name: "Status", Value: mediaStatusLabel(item.Status), Inline: true},
+	}
+	if item.Rating > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Rating", Value: fmt.Sprintf("⭐ %.1f", item.Rating), Inline: true})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		URL:         fmt.Sprintf("https://www.themoviedb.org/%s/%d", item.MediaType, item.ID),
+		Description: Truncate(item.Overview, 1000),
+		Image:       &discordgo.MessageEmbedImage{URL: poster},
+		Color:       0x9c5db3,
+		Author:      &discordgo.MessageEmbedAuthor{Name: heading},
+		Fields:      fields,
+		Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%d of %d · Page %d of %d", pos, total, page, totalPages)},
 	}
 }
@@ -164,60 +164,46 @@ func PlexFixMissingReleaseEmbed(isMovie bool) *discordgo.MessageEmbed {
 	}
 }
 
-func PlexFixMissingReleaseInfoEmbed(rel map[string]any) *discordgo.MessageEmbed {
-	title, _ := rel["title"].(string)
-	if title == "" {
-		if mts, ok := rel["movieTitles"].([]any); ok && len(mts) > 0 {
-			title, _ = mts[0].(string)
-		}
-	}
-	embed := &discordgo.MessageEmbed{Title: "Release Info", Description: title}
+// ReleaseInfo is the indexer release data shown on the /plex-fix-missing
+// release card, independent of whether it came from Radarr or Sonarr.
+type ReleaseInfo struct {
+	Title      string
+	Quality    string
+	Size       int64
+	Indexer    string
+	Seeders    *int
+	Languages  []string
+	Score      int
+	Rejections []string
+}
 
-	// quality
-	if q, ok := rel["quality"].(map[string]any); ok {
-		if qq, ok := q["quality"].(map[string]any); ok {
-			if name, ok := qq["name"].(string); ok {
-				embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Quality", Value: name, Inline: true})
-			}
-		}
+func PlexFixMissingReleaseInfoEmbed(rel ReleaseInfo) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "Release Info", Description: rel.Title}
+
+	if rel.Quality != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Quality", Value: rel.Quality, Inline: true})
 	}
 
-	if size, ok := rel["size"].(float64); ok {
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Size", Value: fmt.Sprintf("%.2f GB", size/(1024*1024*1024)), Inline: true})
+	if rel.Size > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Size", Value: fmt.Sprintf("%.2f GB", float64(rel.Size)/(1024*1024*1024)), Inline: true})
 	}
 
-	if indexer, ok := rel["indexer"].(string); ok && indexer != "" {
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Indexer", Value: indexer, Inline: true})
+	if rel.Indexer != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Indexer", Value: rel.Indexer, Inline: true})
 	}
 
-	if langs, ok := rel["languages"].([]any); ok {
-		ls := []string{}
-		for _, l := range langs {
-			if m, ok := l.(map[string]any); ok {
-				if n, ok := m["name"].(string); ok {
-					ls = append(ls, n)
-				}
-			}
-		}
-		if len(ls) > 0 {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Languages", Value: strings.Join(ls, ", "), Inline: true})
-		}
+	if rel.Seeders != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Seeders", Value: fmt.Sprintf("%d", *rel.Seeders), Inline: true})
 	}
 
-	if sc, ok := rel["customFormatScore"].(float64); ok {
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Score", Value: fmt.Sprintf("%.0f", sc), Inline: true})
+	if len(rel.Languages) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Languages", Value: strings.Join(rel.Languages, ", "), Inline: true})
 	}
 
-	if rjs, ok := rel["rejections"].([]any); ok && len(rjs) > 0 {
-		rs := []string{}
-		for _, r := range rjs {
-			if v, ok := r.(string); ok {
-				rs = append(rs, v)
-			}
-		}
-		if len(rs) > 0 {
-			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Rejections", Value: strings.Join(rs, "\n"), Inline: false})
-		}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Score", Value: fmt.Sprintf("%d", rel.Score), Inline: true})
+
+	if len(rel.Rejections) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Rejections", Value: Truncate(strings.Join(rel.Rejections, "\n"), 1024), Inline: false})
 	}
 
 	return embed
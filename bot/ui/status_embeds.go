@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/KevinHaeusler/go-haruki/bot/health"
+)
+
+// StatusEmbed renders one field per service: green when everything is up,
+// yellow when something reports warnings, red when anything is down.
+func StatusEmbed(results []health.Result) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     "📡 Service Status",
+		Color:     0x2ecc71,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	if len(results) == 0 {
+		embed.Description = "No services are configured."
+		embed.Color = 0x95a5a6
+		return embed
+	}
+
+	down, degraded := 0, 0
+	title := cases.Title(language.English)
+	for _, r := range results {
+		icon := "🟢"
+		switch r.Status {
+		case health.StatusDown:
+			icon = "🔴"
+			down++
+		case health.StatusDegraded:
+			icon = "🟡"
+			degraded++
+		}
+
+		var lines []string
+		if r.OK {
+			lines = append(lines, fmt.Sprintf("%s `%dms`", title.String(r.Status), r.LatencyMS))
+			if r.Version != "" {
+				lines = append(lines, "Version: "+r.Version)
+			}
+			for idx, w := range r.Warnings {
+				if idx == 3 {
+					lines = append(lines, fmt.Sprintf("…and %d more", len(r.Warnings)-idx))
+					break
+				}
+				lines = append(lines, "⚠️ "+Truncate(w, 150))
+			}
+		} else {
+			lines = append(lines, "Down: "+Truncate(r.Error, 200))
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  icon + " " + title.String(r.Name),
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	switch {
+	case down > 0:
+		embed.Color = 0xe74c3c
+		embed.Description = fmt.Sprintf("%d of %d services are down.", down, len(results))
+	case degraded > 0:
+		embed.Color = 0xf1c40f
+		embed.Description = fmt.Sprintf("All services are reachable, %d reporting problems.", degraded)
+	default:
+		embed.Description = "All services are up."
+	}
+	return embed
+}
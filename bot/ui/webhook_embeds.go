@@ -2,7 +2,7 @@ package ui
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	"github.com/KevinHaeusler/go-haruki/bot/webhooks"
@@ -10,7 +10,6 @@ import (
 )
 
 func WebhookNotificationEmbed(p webhooks.NotificationPayload) *discordgo.MessageEmbed {
-	log.Printf("[EMBED] Generating embed. Event: %q, Subject: %q", p.Event, p.Subject)
 	embed := &discordgo.MessageEmbed{
 		Title:       p.Subject,
 		Description: p.Message,
@@ -104,55 +103,43 @@ func WebhookNotificationEmbed(p webhooks.NotificationPayload) *discordgo.Message
 	}
 
 	// Set color based on event type
-	log.Printf("[EMBED] Event for color: %q", p.Event)
 	eventUpper := strings.ToUpper(p.Event)
 	switch {
 	case eventUpper == "MEDIA_AVAILABLE" || strings.Contains(eventUpper, "NOW AVAILABLE"):
-		log.Println("[EMBED] Case MEDIA_AVAILABLE")
 		embed.Color = 0x2ecc71 // Green
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "✅ Media Available"}
 	case eventUpper == "MEDIA_REQUESTED" || strings.Contains(eventUpper, "NEW REQUEST") || strings.Contains(eventUpper, "MEDIA REQUESTED"):
-		log.Println("[EMBED] Case MEDIA_REQUESTED")
 		embed.Color = 0x9c5db3 // Purple
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "📥 New Request"}
 	case eventUpper == "MEDIA_PENDING" || strings.Contains(eventUpper, "PENDING APPROVAL"):
-		log.Println("[EMBED] Case MEDIA_PENDING")
 		embed.Color = 0xe67e22 // Orange
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "⏳ Pending Approval"}
 	case eventUpper == "MEDIA_APPROVED" || strings.Contains(eventUpper, "REQUEST APPROVED"):
-		log.Println("[EMBED] Case MEDIA_APPROVED")
 		embed.Color = 0x2ecc71 // Green
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "✅ Request Approved"}
 	case eventUpper == "MEDIA_DECLINED" || strings.Contains(eventUpper, "REQUEST DECLINED"):
-		log.Println("[EMBED] Case MEDIA_DECLINED")
 		embed.Color = 0xe74c3c // Red
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "❌ Request Declined"}
 	case eventUpper == "MEDIA_FAILED" || strings.Contains(eventUpper, "REQUEST FAILED"):
-		log.Println("[EMBED] Case MEDIA_FAILED")
 		embed.Color = 0xe74c3c // Red
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "❌ Request Failed"}
 	case eventUpper == "MEDIA_AUTO_APPROVED" || strings.Contains(eventUpper, "REQUEST AUTO-APPROVED"):
-		log.Println("[EMBED] Case MEDIA_AUTO_APPROVED")
 		embed.Color = 0x2ecc71 // Green
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "✅ Request Auto-Approved"}
 	case eventUpper == "ISSUE_REPORTED" || strings.Contains(eventUpper, "ISSUE REPORTED"):
-		log.Println("[EMBED] Case ISSUE_REPORTED")
 		embed.Color = 0xe67e22 // Orange
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "⚠️ Issue Reported"}
 	case eventUpper == "ISSUE_COMMENT" || strings.Contains(eventUpper, "NEW COMMENT"):
-		log.Println("[EMBED] Case ISSUE_COMMENT")
 		embed.Color = 0x3498db // Blue
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "💬 New Comment"}
 	case eventUpper == "ISSUE_RESOLVED" || strings.Contains(eventUpper, "ISSUE RESOLVED"):
-		log.Println("[EMBED] Case ISSUE_RESOLVED")
 		embed.Color = 0x2ecc71 // Green
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "✅ Issue Resolved"}
 	case eventUpper == "ISSUE_REOPENED" || strings.Contains(eventUpper, "ISSUE REOPENED"):
-		log.Println("[EMBED] Case ISSUE_REOPENED")
 		embed.Color = 0xe67e22 // Orange
 		embed.Author = &discordgo.MessageEmbedAuthor{Name: "⚠️ Issue Reopened"}
 	default:
-		log.Printf("[EMBED] Unhandled event: %q, keeping default color", p.Event)
+		slog.Debug("unhandled webhook event, keeping default color", "event", p.Event)
 	}
 
 	// Plex link support in footer (if provided via extras as plex_url/plex_link)
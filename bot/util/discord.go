@@ -22,25 +22,16 @@ func GetOptUser(s *discordgo.Session, i *discordgo.InteractionCreate, name strin
 	return nil
 }
 
-// UserHasRole checks if the invoking member has a role ID.
-func UserHasRole(i *discordgo.InteractionCreate, roleID string) bool {
-	if i.Member == nil {
-		return false
+// InteractionUserID returns the ID of the user behind an interaction, in a
+// guild or in DMs.
+func InteractionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
 	}
-	for _, r := range i.Member.Roles {
-		if r == roleID {
-			return true
-		}
-	}
-	return false
-}
-
-// UserIsAdmin checks if the invoking member has Administrator permission.
-func UserIsAdmin(i *discordgo.InteractionCreate) bool {
-	if i.Member == nil {
-		return false
+	if i.User != nil {
+		return i.User.ID
 	}
-	return (i.Member.Permissions & discordgo.PermissionAdministrator) != 0
+	return ""
 }
 
 // RespondEphemeral sends an ephemeral interaction response.
@@ -54,5 +45,15 @@ func RespondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, msg
 	})
 }
 
+// FollowupEphemeral sends an ephemeral follow-up to an interaction that was
+// already acknowledged.
+func FollowupEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, msg string) error {
+	_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: msg,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	return err
+}
+
 // PtrString returns a pointer to the given string (handy for WebhookEdit/MessageEdit fields).
 func PtrString(v string) *string { return &v }
@@ -1,19 +1,35 @@
 package webhooks
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
+
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
 )
 
+// Route is an extra handler served next to the webhook endpoint (e.g. /metrics).
+type Route struct {
+	Path    string
+	Handler http.Handler
+}
+
 // Start starts a minimal HTTP server that exposes a webhook endpoint.
 // addr example: ":8080"
 // path example: "/webhook"
 // authToken is optional. If provided, the "Authorization" header must match it.
-// onNotify is invoked after a successful JSON decode.
-func Start(addr, path, authToken string, onNotify func(NotificationPayload)) (*http.Server, error) {
+// onNotify is invoked after a successful JSON decode, with a context whose
+// logger carries the webhook's correlation ID. routes are served on the same
+// listener without the auth check.
+func Start(addr, path, authToken string, onNotify func(context.Context, NotificationPayload), routes ...Route) (*http.Server, error) {
 	mux := http.NewServeMux()
+	for _, r := range routes {
+		mux.Handle(r.Path, r.Handler)
+	}
 	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		logger := slog.Default().With("cid", logging.NewID(), "source", "webhook")
+
 		if r.Method != http.MethodPost {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
@@ -22,7 +38,7 @@ func Start(addr, path, authToken string, onNotify func(NotificationPayload)) (*h
 		if authToken != "" {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader != authToken && authHeader != ("Bearer "+authToken) {
-				log.Printf("Webhook auth failed. Expected: %s or Bearer %s, Got: %s", authToken, authToken, authHeader)
+				logger.Warn("webhook auth failed", "remote", r.RemoteAddr)
 				w.WriteHeader(http.StatusUnauthorized)
 				return
 			}
@@ -31,21 +47,27 @@ func Start(addr, path, authToken string, onNotify func(NotificationPayload)) (*h
 		defer r.Body.Close()
 		var p NotificationPayload
 		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			logger.Warn("webhook payload is not valid JSON", "err", err)
 			http.Error(w, "invalid JSON", http.StatusBadRequest)
 			return
 		}
 		if onNotify != nil {
-			onNotify(p)
+			onNotify(logging.WithLogger(context.Background(), logger), p)
 		}
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
 
-	srv := &http.Server{Addr: addr, Handler: mux}
+	return Serve(addr, mux), nil
+}
+
+// Serve runs handler on addr in the background.
+func Serve(addr string, handler http.Handler) *http.Server {
+	srv := &http.Server{Addr: addr, Handler: handler}
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("webhook server error: %v", err)
+			slog.Error("http server error", "addr", addr, "err", err)
 		}
 	}()
-	return srv, nil
+	return srv
 }
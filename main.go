@@ -1,27 +1,41 @@
 package main
 
 import (
-	"log"
+	"flag"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/KevinHaeusler/go-haruki/bot"
+	"github.com/KevinHaeusler/go-haruki/bot/config"
+	"github.com/KevinHaeusler/go-haruki/bot/logging"
 	"github.com/joho/godotenv"
 )
 
 func main() {
 	_ = godotenv.Load()
 
-	token := os.Getenv("BOT_TOKEN")
-	if token == "" {
-		log.Fatal("BOT_TOKEN missing in environment")
+	configPath := flag.String("config", os.Getenv("HARUKI_CONFIG"), "path to the YAML config file (env vars override it)")
+	flag.Parse()
+
+	// fall back to ./config.yaml when present, env-only otherwise
+	if *configPath == "" {
+		if _, err := os.Stat("config.yaml"); err == nil {
+			*configPath = "config.yaml"
+		}
 	}
 
-	guildID := os.Getenv("GUILD_ID") // optional
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		slog.Error("load config", "err", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log.Level, cfg.Log.Format))
 
-	if err := bot.Start(token, guildID); err != nil {
-		log.Fatal(err)
+	if err := bot.Start(cfg); err != nil {
+		slog.Error("start bot", "err", err)
+		os.Exit(1)
 	}
 	defer bot.Stop()
 